@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// fetchAllEpisodesMsg carries a series' episodes, grouped under season headers
+type fetchAllEpisodesMsg []list.Item
+
+// openAllEpisodes lists every episode of a series in one list
+func (m Model) openAllEpisodes(series MediaItem) (Model, tea.Cmd) {
+	m.allEpisodesList.SetItems([]list.Item{})
+	m.allEpisodesList.Title = displayText(series.Title()) + " · All Episodes"
+	m.currentView = "allepisodes"
+	ctx := m.viewContext()
+	return m, fetchAllEpisodes(ctx, m.client, series.ID)
+}
+
+// updateAllEpisodes handles input in the all episodes view; [ and ] jump
+// between seasons
+func (m Model) updateAllEpisodes(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.allEpisodesList, cmd = m.allEpisodesList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" && m.allEpisodesList.FilterState() != list.Filtering {
+		if selectedItem, ok := m.allEpisodesList.SelectedItem().(MediaItem); ok && selectedItem.ID != "" {
+			return m.drillInto(selectedItem)
+		}
+	}
+
+	return m, cmd
+}
+
+// Command to fetch every episode of a series
+func fetchAllEpisodes(ctx context.Context, client *jellyfin.Client, seriesID string) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetAllEpisodes(ctx, seriesID)
+		if err != nil {
+			return fetchError(err)
+		}
+		return fetchAllEpisodesMsg(groupBySeason(client, items))
+	}
+}
+
+// groupBySeason converts episodes in season order, adding a header before
+// each season
+func groupBySeason(client *jellyfin.Client, items []jellyfin.MediaItem) []list.Item {
+	var listItems []list.Item
+	headerAt := -1
+	for i, item := range items {
+		if i == 0 || item.ParentIndexNumber != items[i-1].ParentIndexNumber {
+			listItems = append(listItems, sectionHeader{title: seasonName(item)})
+			headerAt = len(listItems) - 1
+		}
+
+		header := listItems[headerAt].(sectionHeader)
+		header.count++
+		listItems[headerAt] = header
+
+		mediaItem := convertItems(client, []jellyfin.MediaItem{item})[0]
+		mediaItem.ParentID = item.SeasonID
+		mediaItem.IndexNumber = item.IndexNumber
+		if item.IndexNumber > 0 {
+			mediaItem.DisplayTitle = fmt.Sprintf("E%02d: %s", item.IndexNumber, item.Name)
+		}
+		listItems = append(listItems, mediaItem)
+	}
+	return listItems
+}
+
+// seasonName names an episode's season for its header
+func seasonName(item jellyfin.MediaItem) string {
+	switch {
+	case item.SeasonName != "":
+		return item.SeasonName
+	case item.ParentIndexNumber == 0:
+		return "Specials"
+	default:
+		return fmt.Sprintf("Season %d", item.ParentIndexNumber)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// Movies and TV shows browsed together in one list, newest first
+type fetchAllMediaMsg []MediaItem
+
+// openAllMedia lists movies and TV shows together
+func (m Model) openAllMedia() (Model, tea.Cmd) {
+	m.returnTo["allmedia"] = m.currentView
+	m.currentView = "allmedia"
+	ctx := m.viewContext()
+	return m, fetchAllMedia(ctx, m.client)
+}
+
+// updateAllMedia handles input in the all media view
+func (m Model) updateAllMedia(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.allMediaList, cmd = m.allMediaList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" && m.allMediaList.FilterState() != list.Filtering {
+		if selectedItem, ok := m.allMediaList.SelectedItem().(MediaItem); ok && selectedItem.ID != "" {
+			return m.drillInto(selectedItem)
+		}
+	}
+
+	return m, cmd
+}
+
+// Command to fetch movies and TV shows, badged with their type
+func fetchAllMedia(ctx context.Context, client *jellyfin.Client) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetAllMedia(ctx)
+		if err != nil {
+			return fetchError(err)
+		}
+
+		mediaItems := convertItems(client, items)
+		for i, item := range items {
+			mediaItems[i].DisplayTitle = typeBadge(item.Type) + searchTitle(item)
+		}
+		return fetchAllMediaMsg(mediaItems)
+	}
+}
+
+// typeBadge labels what an item is in mixed lists, e.g. "[Movie] "
+func typeBadge(itemType string) string {
+	switch itemType {
+	case "Movie":
+		return "[Movie] "
+	case "Series":
+		return "[Series] "
+	case "Episode":
+		return "[Episode] "
+	}
+	return ""
+}
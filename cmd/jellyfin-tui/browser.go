@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openInBrowser opens a URL in the default browser. Without a graphical
+// session (e.g. over SSH) the URL is shown instead so it can be copied.
+func openInBrowser(url string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("cmd", "/c", "start", "", url)
+		default:
+			if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+				return statusMsg("No browser available, open: " + url)
+			}
+			cmd = exec.Command("xdg-open", url)
+		}
+
+		if err := cmd.Start(); err != nil {
+			return statusMsg(fmt.Sprintf("Couldn't open a browser (%v), open: %s", err, url))
+		}
+		// Reap the launcher so it doesn't linger as a zombie
+		go cmd.Wait()
+
+		return statusMsg("Opened in browser: " + url)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmation is a yes/no question asked on the status line; its action
+// runs if the answer is y, and decline, if set, if it's n
+type confirmation struct {
+	prompt  string
+	action  tea.Cmd
+	decline tea.Cmd
+}
+
+// askConfirm asks before running an action
+func (m Model) askConfirm(prompt string, action tea.Cmd) (Model, tea.Cmd) {
+	m.confirm = &confirmation{prompt: prompt, action: action}
+	m.status = prompt + " (y/n)"
+	return m, nil
+}
+
+// askChoice asks a question where both answers do something; any key but
+// y or n cancels
+func (m Model) askChoice(prompt string, yes, no tea.Cmd) (Model, tea.Cmd) {
+	m.confirm = &confirmation{prompt: prompt, action: yes, decline: no}
+	m.status = prompt + " (y/n, esc cancels)"
+	return m, nil
+}
+
+// updateConfirm answers the pending confirmation; any key but y, or n for a
+// choice, cancels
+func (m Model) updateConfirm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	confirm := m.confirm
+	m.confirm = nil
+	switch {
+	case msg.String() == "y":
+		m.status = ""
+		return m, confirm.action
+	case msg.String() == "n" && confirm.decline != nil:
+		m.status = ""
+		return m, confirm.decline
+	}
+	m.status = "Cancelled"
+	return m, nil
+}
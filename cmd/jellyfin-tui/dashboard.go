@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// dashboardLimit is how many items each dashboard section shows
+const dashboardLimit = 10
+
+// Dashboard sections, in display order
+const (
+	sectionContinue = iota
+	sectionNextUp
+	sectionLatest
+	sectionCount
+)
+
+var sectionTitles = [sectionCount]string{"Continue Watching", "Next Up", "Recently Added"}
+
+// dashboardSectionMsg carries the items for one dashboard section
+type dashboardSectionMsg struct {
+	section int
+	items   []MediaItem
+}
+
+// openDashboard shows the dashboard and fetches its sections concurrently
+func (m Model) openDashboard() (Model, tea.Cmd) {
+	m.currentView = "dashboard"
+	m.dashboardSections = [sectionCount][]MediaItem{}
+	m.dashboardList.SetItems([]list.Item{})
+	ctx := m.viewContext()
+	return m, tea.Batch(
+		fetchDashboardSection(ctx, m.client, sectionContinue),
+		fetchDashboardSection(ctx, m.client, sectionNextUp),
+		fetchDashboardSection(ctx, m.client, sectionLatest),
+	)
+}
+
+// setDashboardSection stores a section's items and rebuilds the list,
+// keeping the sections in order however the fetches complete
+func (m *Model) setDashboardSection(msg dashboardSectionMsg) {
+	m.dashboardSections[msg.section] = msg.items
+
+	var items []list.Item
+	for section, sectionItems := range m.dashboardSections {
+		if len(sectionItems) == 0 {
+			continue
+		}
+		items = append(items, sectionHeader{title: sectionTitles[section], count: len(sectionItems)})
+		items = append(items, convertToListItems(sectionItems)...)
+	}
+	m.dashboardList.SetItems(items)
+	skipHeaders(&m.dashboardList, false)
+}
+
+// updateDashboard handles input in the dashboard view
+func (m Model) updateDashboard(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.dashboardList, cmd = m.dashboardList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" && m.dashboardList.FilterState() != list.Filtering {
+		// Headers aren't MediaItems, so selecting one does nothing
+		if selectedItem, ok := m.dashboardList.SelectedItem().(MediaItem); ok && selectedItem.ID != "" {
+			return m.drillInto(selectedItem)
+		}
+	}
+
+	return m, cmd
+}
+
+// Command to fetch one dashboard section
+func fetchDashboardSection(ctx context.Context, client *jellyfin.Client, section int) tea.Cmd {
+	return func() tea.Msg {
+		var items []jellyfin.MediaItem
+		var err error
+		switch section {
+		case sectionContinue:
+			items, err = client.GetResumeItems(ctx, dashboardLimit)
+		case sectionNextUp:
+			items, err = client.GetNextUp(ctx, dashboardLimit)
+		case sectionLatest:
+			items, err = client.GetLatest(ctx, dashboardLimit, false)
+		}
+		if err != nil {
+			return fetchError(err)
+		}
+
+		mediaItems := convertItems(client, items)
+		for i, item := range items {
+			if item.Type == "Episode" {
+				mediaItems[i].DisplayTitle = episodeTitle(item)
+			}
+			if section == sectionContinue && item.UserData.PlayedPercentage > 0 {
+				mediaItems[i].DisplayDesc = fmt.Sprintf("%s · %.0f%% watched", item.Type, item.UserData.PlayedPercentage)
+			}
+		}
+		return dashboardSectionMsg{section: section, items: mediaItems}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// The play and open commands take the ID of an item from another tool, such
+// as a script or a launcher, and play it straight away or start the app on
+// its details.
+
+// deepLinkTimeout bounds looking up the item
+const deepLinkTimeout = 15 * time.Second
+
+// itemIDPattern matches Jellyfin item IDs: 32 hexadecimal digits, with or
+// without the dashes of a GUID
+var itemIDPattern = regexp.MustCompile(`^(?:[0-9a-fA-F]{32}|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
+// resolveItem looks up an item by ID, explaining IDs that aren't valid or
+// that the server doesn't know
+func resolveItem(client *jellyfin.Client, itemID string) (MediaItem, jellyfin.ItemDetails, error) {
+	if !itemIDPattern.MatchString(itemID) {
+		return MediaItem{}, jellyfin.ItemDetails{}, fmt.Errorf("%q isn't an item ID; IDs are 32 hexadecimal digits, as in the web client's item URLs", itemID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), deepLinkTimeout)
+	defer cancel()
+
+	details, err := client.GetItemDetails(ctx, itemID)
+	var statusErr *jellyfin.StatusError
+	if errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusBadRequest) {
+		return MediaItem{}, details, fmt.Errorf("the server has no item with ID %s", itemID)
+	}
+	if err != nil {
+		return MediaItem{}, details, fmt.Errorf("couldn't look up item %s: %v", itemID, err)
+	}
+	return convertItems(client, []jellyfin.MediaItem{details.MediaItem})[0], details, nil
+}
+
+// runPlay plays an item in mpv, resuming where it was left off, and reports
+// its playback until mpv exits. Items that can't be played, like a series,
+// are refused with a pointer to the open command. With printOnly, the
+// player command is shown instead. It returns the process exit code.
+func runPlay(w io.Writer, config Config, itemID string, printOnly bool) int {
+	client := newClient(config)
+	item, _, err := resolveItem(client, itemID)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return 1
+	}
+
+	switch {
+	case item.ItemType == "Book":
+		fmt.Fprintf(w, "%s is an ebook; ebooks aren't supported, open it in the Jellyfin web client\n", item.Title())
+		return 1
+	case item.IsFolder || item.StreamURL == "":
+		fmt.Fprintf(w, "%s (%s) can't be played; run \"jellyfin-tui open %s\" to browse it\n", item.Title(), item.ItemType, itemID)
+		return 1
+	}
+
+	if isVideo(item) {
+		ctx, cancel := context.WithTimeout(context.Background(), deepLinkTimeout)
+		if parts, err := client.GetAdditionalParts(ctx, item.ID); err == nil {
+			item.Parts = convertItems(client, parts)
+		}
+		cancel()
+	}
+
+	m := Model{config: config, client: client}
+	if printOnly {
+		fmt.Fprintln(w, displayCommand(m.playerCommand(item), client.Headers))
+		return 0
+	}
+
+	// Stop the way the app does when interrupted, sending the final report
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	p := newPlayer(config)
+	for {
+		if startErr, ok := p.play(client, item, m.playerCommand(item))().(errorMsg); ok {
+			fmt.Fprintf(w, "Error: %v\n", startErr)
+			return 1
+		}
+		fmt.Fprintf(w, "Playing %s\n", item.Title())
+
+		done := make(chan struct{})
+		go func() {
+			p.trackers.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-interrupt:
+			p.shutdown(config.StopPlayerOnExit, shutdownTimeout, interrupt)
+			return 0
+		}
+
+		fallback, ok := nextFallback(p)
+		if !ok {
+			return 0
+		}
+		fmt.Fprintf(w, "MPV couldn't play %s directly (%v); retrying as a transcoded stream\n", item.Title(), fallback.err)
+		item = fallback.item
+		item.Limited = true
+	}
+}
+
+// nextFallback returns the video mpv couldn't play directly, if playback
+// asked for it to be played again transcoded
+func nextFallback(p *player) (transcodeFallbackMsg, bool) {
+	for {
+		select {
+		case msg := <-p.events:
+			if fallback, ok := msg.(transcodeFallbackMsg); ok {
+				return fallback, true
+			}
+		default:
+			return transcodeFallbackMsg{}, false
+		}
+	}
+}
+
+// openItem starts the app on the details of an item; esc goes back to the
+// view it would otherwise have started in
+func (m Model) openItem(itemID string) (Model, error) {
+	item, details, err := resolveItem(m.client, itemID)
+	if err != nil {
+		return m, err
+	}
+	m.returnTo["info"] = m.currentView
+	m.currentView = "info"
+	m.infoItem, m.infoDetails = item, &details
+	return m, nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// indicatorSymbols are the glyphs marking an item's watched and favorite state
+type indicatorSymbols struct {
+	Watched   string
+	Unwatched string
+	Partial   string
+	Favorite  string
+}
+
+// asciiSymbols render on any terminal; unicodeSymbols are opt-in
+var (
+	asciiSymbols   = indicatorSymbols{Watched: "x", Unwatched: "-", Partial: "~", Favorite: "*"}
+	unicodeSymbols = indicatorSymbols{Watched: "✓", Unwatched: "○", Partial: "◐", Favorite: "★"}
+)
+
+// indicatorSymbols returns the configured symbols. The watched, unwatched and
+// partial symbols share a column, so they must be one cell wide; invalid
+// ones are replaced by the defaults and reported in the error.
+func (c Config) indicatorSymbols() (indicatorSymbols, error) {
+	symbols := asciiSymbols
+	if c.UnicodeSymbols {
+		symbols = unicodeSymbols
+	}
+
+	var invalid []string
+	column := func(name, value string, symbol *string) {
+		if value == "" {
+			return
+		}
+		if lipgloss.Width(value) != 1 {
+			invalid = append(invalid, name)
+			return
+		}
+		*symbol = value
+	}
+	column("watched_symbol", c.WatchedSymbol, &symbols.Watched)
+	column("unwatched_symbol", c.UnwatchedSymbol, &symbols.Unwatched)
+	column("partial_symbol", c.PartialSymbol, &symbols.Partial)
+	if c.FavoriteSymbol != "" {
+		symbols.Favorite = c.FavoriteSymbol
+	}
+
+	if len(invalid) > 0 {
+		return symbols, fmt.Errorf("ignoring %s: must be a single character wide", strings.Join(invalid, ", "))
+	}
+	return symbols, nil
+}
+
+// watchState is how much of an item has been watched
+type watchState int
+
+const (
+	stateUnwatched watchState = iota
+	statePartial
+	stateWatched
+	stateUnknown // The server sent no user data for the item
+)
+
+// unknownSymbol stands in for the watched-state symbol of items whose state
+// isn't known, keeping titles aligned
+const unknownSymbol = " "
+
+// itemWatchState returns how much of an item has been watched
+func itemWatchState(item MediaItem) watchState {
+	switch {
+	case !item.UserData.Known:
+		return stateUnknown
+	case item.UserData.Played:
+		return stateWatched
+	case item.UserData.PlaybackPositionTicks > 0 || item.UserData.PlayedPercentage > 0:
+		return statePartial
+	}
+	return stateUnwatched
+}
+
+// Default title colors by watched state
+const (
+	defaultWatchedColor    = "241"
+	defaultInProgressColor = "214"
+)
+
+// stateColors returns the title color of each watched state that has one
+func (c Config) stateColors() map[watchState]lipgloss.Color {
+	colors := map[watchState]lipgloss.Color{}
+	set := func(state watchState, value, fallback string) {
+		if value == "" {
+			value = fallback
+		}
+		if value != "" && value != "none" {
+			colors[state] = lipgloss.Color(value)
+		}
+	}
+	set(stateWatched, c.WatchedColor, defaultWatchedColor)
+	set(statePartial, c.InProgressColor, defaultInProgressColor)
+	set(stateUnwatched, c.UnwatchedColor, "")
+	return colors
+}
+
+// itemDelegate renders list items with their watched and favorite
+// indicators, and their titles colored by watched state. The detailed
+// layout shows a title and description over two lines; the compact one
+// shows only the title, one item per line, for small terminals.
+type itemDelegate struct {
+	list.DefaultDelegate
+	symbols indicatorSymbols
+	colors  map[watchState]lipgloss.Color
+}
+
+// newItemDelegate returns the delegate used by every list view
+func newItemDelegate(config Config) list.ItemDelegate {
+	delegate := list.NewDefaultDelegate()
+	if config.CompactLists {
+		delegate.ShowDescription = false
+		delegate.SetSpacing(0)
+	}
+
+	symbols, _ := config.indicatorSymbols()
+	return itemDelegate{DefaultDelegate: delegate, symbols: symbols, colors: config.stateColors()}
+}
+
+// Render prefixes media items with their indicators, colors them by watched
+// state, and fits their title and description to the width of the list.
+// Only the normal styles are colored, so the highlighted item keeps the
+// cursor's.
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	delegate := d.DefaultDelegate
+	width := m.Width() - delegate.Styles.NormalTitle.GetHorizontalPadding()
+	if mediaItem, ok := item.(MediaItem); ok {
+		state := itemWatchState(mediaItem)
+		if color, ok := d.colors[state]; ok && mediaItem.ItemType != "" {
+			delegate.Styles.NormalTitle = delegate.Styles.NormalTitle.Foreground(color)
+			if state == stateWatched {
+				delegate.Styles.NormalDesc = delegate.Styles.NormalDesc.Foreground(color)
+			}
+		}
+
+		title := displayText(mediaItem.Title())
+		if mediaItem.ItemType != "" {
+			title = d.indicators(mediaItem) + " " + title
+		}
+		if badge := newEpisodesBadge(mediaItem); badge != "" {
+			title = truncateText(title, width-lipgloss.Width(badge)-1) + " " + badge
+		} else {
+			title = truncateText(title, width)
+		}
+		mediaItem.DisplayTitle = title
+		mediaItem.DisplayDesc = truncateText(displayText(mediaItem.Description()), width)
+		mediaItem.Resolution = "" // Already part of the description
+		item = mediaItem
+	}
+	delegate.Render(w, m, index, item)
+}
+
+// indicators returns the watched-state symbol, followed by the favorite
+// symbol for favorites
+func (d itemDelegate) indicators(item MediaItem) string {
+	status := d.symbols.Unwatched
+	switch itemWatchState(item) {
+	case stateWatched:
+		status = d.symbols.Watched
+	case statePartial:
+		status = d.symbols.Partial
+	case stateUnknown:
+		status = unknownSymbol
+	}
+	if item.UserData.IsFavorite {
+		return status + " " + d.symbols.Favorite
+	}
+	return status
+}
+
+// sectionHeader labels a group of items in a list; the cursor skips over
+// it, so it can't be selected or played
+type sectionHeader struct {
+	title string
+	count int
+}
+
+func (h sectionHeader) Title() string       { return fmt.Sprintf("── %s (%d) ──", h.title, h.count) }
+func (h sectionHeader) Description() string { return "" }
+func (h sectionHeader) FilterValue() string { return "" }
+
+// Update keeps the cursor off section headers, and jumps between sections
+// with [ and ]
+func (d itemDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	up := false
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "]":
+			jumpSection(m, 1)
+		case "[":
+			jumpSection(m, -1)
+		default:
+			up = key.Matches(keyMsg, m.KeyMap.CursorUp)
+		}
+	}
+	skipHeaders(m, up)
+	return d.DefaultDelegate.Update(msg, m)
+}
+
+// isHeader reports whether a list item is a section header
+func isHeader(item list.Item) bool {
+	_, ok := item.(sectionHeader)
+	return ok
+}
+
+// skipHeaders moves the cursor off a section header, continuing in the
+// direction it was moving, or the other way at the ends of the list
+func skipHeaders(m *list.Model, up bool) {
+	items := m.VisibleItems()
+	index := m.Index()
+	if index < 0 || index >= len(items) || !isHeader(items[index]) {
+		return
+	}
+
+	step := 1
+	if up {
+		step = -1
+	}
+	for _, dir := range []int{step, -step} {
+		for i := index + dir; i >= 0 && i < len(items); i += dir {
+			if !isHeader(items[i]) {
+				m.Select(i)
+				return
+			}
+		}
+	}
+}
+
+// jumpSection moves the cursor to the first item of the next (dir 1) or
+// previous (dir -1) section
+func jumpSection(m *list.Model, dir int) {
+	items := m.VisibleItems()
+
+	// Find the header of the current section
+	current := m.Index()
+	for current >= 0 && current < len(items) && !isHeader(items[current]) {
+		current--
+	}
+
+	for i := current + dir; i >= 0 && i < len(items); i += dir {
+		if isHeader(items[i]) {
+			m.Select(i)
+			return
+		}
+	}
+}
+
+// setCompact switches every list between the compact and detailed delegates
+func (m *Model) setCompact(compact bool) {
+	m.config.CompactLists = compact
+	delegate := newItemDelegate(m.config)
+	for _, l := range m.allLists() {
+		index := l.Index()
+		l.SetDelegate(delegate)
+		// Items per page changed, so move the paginator back to the cursor
+		l.Select(index)
+	}
+}
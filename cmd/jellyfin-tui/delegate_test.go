@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+func TestItemWatchState(t *testing.T) {
+	tests := []struct {
+		name     string
+		userData jellyfin.UserData
+		want     watchState
+	}{
+		{"no user data", jellyfin.UserData{}, stateUnknown},
+		{"unwatched", jellyfin.UserData{Known: true}, stateUnwatched},
+		{"started", jellyfin.UserData{Known: true, PlaybackPositionTicks: 1}, statePartial},
+		{"started, by percentage", jellyfin.UserData{Known: true, PlayedPercentage: 12.5}, statePartial},
+		{"watched", jellyfin.UserData{Known: true, Played: true, PlaybackPositionTicks: 1}, stateWatched},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := itemWatchState(MediaItem{UserData: tt.userData}); got != tt.want {
+				t.Errorf("itemWatchState = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
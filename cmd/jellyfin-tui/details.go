@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The details panel shows the poster, overview and metadata of the
+// highlighted item beside the list on wide terminals. Details are fetched
+// once the cursor has rested on an item for detailsDelay, so scrolling
+// through a list doesn't fetch everything it passes, and are kept for the
+// rest of the session.
+
+const (
+	defaultDetailsMinWidth = 100 // Narrower terminals show the list alone
+	maxDetailsWidth        = 60  // Cells
+	detailsDelay           = 300 * time.Millisecond
+)
+
+// detailsDueMsg fires once the cursor has rested on an item
+type detailsDueMsg MediaItem
+
+// detailsWidth returns the width of the details panel for the available
+// space, or 0 if it's turned off or the terminal is too narrow
+func (c Config) detailsWidth(availWidth int) int {
+	minWidth := c.DetailsMinWidth
+	if minWidth <= 0 {
+		minWidth = defaultDetailsMinWidth
+	}
+	if !c.ShowDetails || availWidth < minWidth {
+		return 0
+	}
+	return min(maxDetailsWidth, availWidth*2/5)
+}
+
+// toggleDetails shows or hides the details panel and saves the choice
+func (m Model) toggleDetails() (Model, tea.Cmd) {
+	m.config.ShowDetails = !m.config.ShowDetails
+	m.layout()
+	if m.config.ShowDetails && m.detailsWidth == 0 {
+		m.status = "The details panel shows once the window is wider"
+	}
+	if err := saveConfig(m.config); err != nil {
+		m.status = fmt.Sprintf("Failed to save details preference: %v", err)
+	}
+	return m, nil
+}
+
+// highlightedItem returns the item highlighted in the current view's list
+func (m Model) highlightedItem() (MediaItem, bool) {
+	l := m.listForView(m.currentView)
+	if l == nil {
+		return MediaItem{}, false
+	}
+	item, ok := l.SelectedItem().(MediaItem)
+	return item, ok && item.ID != ""
+}
+
+// loadDetails waits for the cursor to rest on the highlighted item before
+// fetching its details, unless they're cached or already on their way.
+// Details that failed to load are tried again once the cursor has moved off
+// the item and back.
+func (m Model) loadDetails() (Model, tea.Cmd) {
+	if m.detailsWidth == 0 {
+		return m, nil
+	}
+	item, ok := m.highlightedItem()
+	if !ok || item.ID == m.detailsPending || item.ID == m.detailsFailed {
+		return m, nil
+	}
+	m.detailsFailed, m.detailsErr = "", nil
+	if _, ok := m.detailsCache[item.ID]; ok {
+		return m, nil
+	}
+	m.detailsPending = item.ID
+	return m, tea.Tick(detailsDelay, func(time.Time) tea.Msg {
+		return detailsDueMsg(item)
+	})
+}
+
+// fetchRestingDetails fetches the details of an item if the cursor is still
+// on it, along with the view's other fetches
+func (m Model) fetchRestingDetails(msg detailsDueMsg) (Model, tea.Cmd) {
+	if item, ok := m.highlightedItem(); !ok || item.ID != msg.ID || m.detailsWidth == 0 {
+		if m.detailsPending == msg.ID {
+			m.detailsPending = ""
+		}
+		return m, nil
+	}
+	ctx := m.fetchCtx
+	if ctx == nil || ctx.Err() != nil {
+		ctx = m.viewContext()
+	}
+	return m, fetchItemDetails(ctx, m.client, MediaItem(msg))
+}
+
+// detailsView renders the panel for the highlighted item: its poster, then
+// its details, cut to the height of the list
+func (m Model) detailsView() string {
+	item, ok := m.highlightedItem()
+	if !ok {
+		return ""
+	}
+	var panel string
+	if item.ID == m.detailsFailed {
+		panel = infoTitleStyle.Render(truncateText(displayText(mediaTitle(item)), m.detailsWidth)) + "\n\n" +
+			lipgloss.NewStyle().Width(m.detailsWidth).Render(infoDimStyle.Render(fmt.Sprintf("Couldn't load details: %v", m.detailsErr)))
+	} else {
+		panel = renderDetails(item, m.detailsCache[item.ID], m.detailsWidth)
+	}
+	if poster := m.previewView(); poster != "" {
+		panel = poster + "\n\n" + panel
+	}
+	return lipgloss.NewStyle().Width(m.detailsWidth).MaxHeight(m.availHeight).Render(panel)
+}
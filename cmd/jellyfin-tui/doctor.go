@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// doctorTimeout bounds each diagnostic step
+const doctorTimeout = 15 * time.Second
+
+// doctorStep is one check run by the doctor command. It returns a detail
+// to show when it passes.
+type doctorStep struct {
+	name  string
+	check func(ctx context.Context, client *jellyfin.Client) (string, error)
+}
+
+var doctorSteps = []doctorStep{
+	{"Server reachable", func(ctx context.Context, client *jellyfin.Client) (string, error) {
+		return "", client.Ping(ctx)
+	}},
+	{"Authentication", func(ctx context.Context, client *jellyfin.Client) (string, error) {
+		info, err := client.GetSystemInfo(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s, Jellyfin %s", info.ServerName, info.Version), nil
+	}},
+	{"Libraries", func(ctx context.Context, client *jellyfin.Client) (string, error) {
+		libraries, err := client.GetLibraries(ctx)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d found", len(libraries)), nil
+	}},
+	{"Sample item fetch", func(ctx context.Context, client *jellyfin.Client) (string, error) {
+		items, err := client.GetLatest(ctx, 1, false)
+		if err != nil {
+			return "", err
+		}
+		if len(items) == 0 {
+			return "no items", nil
+		}
+		return fmt.Sprintf("%s (%s)", items[0].Name, items[0].Type), nil
+	}},
+}
+
+// runDoctor checks each layer of the connection to the server in turn,
+// so a failure shows whether the problem is the network, the API key or
+// the responses. Steps after a failure are skipped, since they depend on
+// it. It returns the process exit code.
+func runDoctor(w io.Writer, config Config) int {
+	fmt.Fprintf(w, "Server: %s\n", config.ServerURL)
+	if config.APIKey == "" {
+		fmt.Fprintln(w, "API key: not set")
+	} else {
+		fmt.Fprintln(w, "API key: set")
+	}
+	fmt.Fprintln(w)
+
+	client := newClient(config)
+	failed := false
+	for _, step := range doctorSteps {
+		if failed {
+			fmt.Fprintf(w, "SKIP  %s\n", step.name)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+		start := time.Now()
+		detail, err := step.check(ctx, client)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		cancel()
+
+		if err != nil {
+			failed = true
+			detail = tokenParam.ReplaceAllString(err.Error(), "${1}REDACTED")
+			fmt.Fprintf(w, "FAIL  %-18s %8s  %s\n", step.name, elapsed, detail)
+			continue
+		}
+		fmt.Fprintf(w, "PASS  %-18s %8s  %s\n", step.name, elapsed, detail)
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// reservedHeaders are set by the app itself, so extra headers can't
+// replace them
+var reservedHeaders = []string{"Authorization", "X-Emby-Authorization", "X-Emby-Token", "Accept"}
+
+// extraHeaders returns the headers configured to be sent with every
+// request, such as those an authenticating proxy needs. Invalid names or
+// values, and headers the app sets itself, are left out and reported in the
+// error.
+func (c Config) extraHeaders() (http.Header, error) {
+	headers := http.Header{}
+	var invalid []string
+	for name, value := range c.ExtraHeaders {
+		if !validHeaderName(name) || strings.ContainsAny(value, "\r\n") ||
+			slices.Contains(reservedHeaders, http.CanonicalHeaderKey(name)) {
+			invalid = append(invalid, name)
+			continue
+		}
+		headers.Set(name, value)
+	}
+
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return headers, fmt.Errorf("ignoring extra_headers %s: invalid, or set by the app itself", strings.Join(invalid, ", "))
+	}
+	return headers, nil
+}
+
+// validHeaderName reports whether a header name is an HTTP token
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// headerFile writes the extra headers to an mpv config file that only the
+// user can read, for mpv to load with --include. mpv fetches streams itself
+// and needs them too, but passed as arguments they'd show in the process
+// list to every user. The caller removes the file.
+func headerFile(headers http.Header) (string, error) {
+	file, err := os.CreateTemp("", "jellyfin-tui-headers-*.conf") // Created 0600
+	if err != nil {
+		return "", fmt.Errorf("failed to write extra headers for MPV: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(headerConfig(headers)); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write extra headers for MPV: %v", err)
+	}
+	return file.Name(), nil
+}
+
+// headerConfig returns the mpv config lines adding each header. -append
+// adds one field, so commas in a value are kept, and the %length% form
+// takes the field as-is, whatever characters it holds.
+func headerConfig(headers http.Header) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		for _, value := range headers[name] {
+			field := name + ": " + value
+			fmt.Fprintf(&sb, "http-header-fields-append=%%%d%%%s\n", len(field), field)
+		}
+	}
+	return sb.String()
+}
+
+// withInclude adds an mpv --include of a config file to the player command
+func withInclude(args []string, path string) []string {
+	return append([]string{args[0], "--include=" + path}, args[1:]...)
+}
+
+// displayCommand returns the player command as print-play shows it, safe to
+// display: API keys are redacted, and the extra headers, passed in a file
+// that's only written when mpv is started, are stood in for by a placeholder
+func displayCommand(args []string, headers http.Header) string {
+	if len(headers) > 0 {
+		args = withInclude(args, "<extra headers file>")
+	}
+	return formatCommand(redactArgs(args))
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// defaultHealthCheckInterval is used when the config doesn't set one
+const defaultHealthCheckInterval = 30 * time.Second
+
+// Connection states shown in the footer
+const (
+	connectionUnknown = iota
+	connectionOnline
+	connectionOffline
+)
+
+// healthTickMsg triggers a health check
+type healthTickMsg struct{}
+
+// healthResultMsg carries the result of a health check
+type healthResultMsg struct{ err error }
+
+// healthCheckInterval returns how often the server is pinged, or 0 if
+// health checks are disabled
+func (c Config) healthCheckInterval() time.Duration {
+	switch {
+	case c.HealthCheckInterval < 0:
+		return 0
+	case c.HealthCheckInterval == 0:
+		return defaultHealthCheckInterval
+	}
+	return time.Duration(c.HealthCheckInterval) * time.Second
+}
+
+// scheduleHealthCheck waits out the interval before the next check. It's
+// only called once the previous check has answered, so a slow server never
+// has more than one ping in flight.
+func scheduleHealthCheck(interval time.Duration) tea.Cmd {
+	if interval == 0 {
+		return nil
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg { return healthTickMsg{} })
+}
+
+// Command to ping the server, giving up after the check interval
+func pingServer(client *jellyfin.Client, timeout time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		return healthResultMsg{err: client.Ping(ctx)}
+	}
+}
+
+// updateHealth records a health check result, refreshing the current view
+// when the server comes back if the config asks for it
+func (m Model) updateHealth(msg healthResultMsg) (Model, tea.Cmd) {
+	next := scheduleHealthCheck(m.config.healthCheckInterval())
+
+	if msg.err != nil {
+		m.connection = connectionOffline
+		return m, next
+	}
+
+	recovered := m.connection == connectionOffline
+	m.connection = connectionOnline
+	if !recovered || !m.config.RefreshOnReconnect {
+		return m, next
+	}
+
+	// Errors from while the server was away are stale now
+	m.err = nil
+	m.status = "Reconnected; refreshing"
+	m, refresh := m.refreshView()
+	return m, tea.Batch(refresh, next)
+}
+
+// refreshView refetches the current view's items
+func (m Model) refreshView() (Model, tea.Cmd) {
+	switch m.currentView {
+	case "dashboard":
+		return m.openDashboard()
+	case "latest":
+		ctx := m.viewContext()
+		return m, fetchLatest(ctx, m.client, m.hideWatchedLatest)
+	case "movies":
+		ctx := m.viewContext()
+		return m, fetchMovies(ctx, m.client)
+	case "tvshows":
+		ctx := m.viewContext()
+		return m, fetchTVShows(ctx, m.client)
+	case "allmedia":
+		ctx := m.viewContext()
+		return m, fetchAllMedia(ctx, m.client)
+	case "recent":
+		ctx := m.viewContext()
+		return m, fetchRecent(ctx, m.client)
+	case "seasons":
+		ctx := m.viewContext()
+		return m, fetchSeasons(ctx, m.client, m.series.ID)
+	case "episodes":
+		ctx := m.viewContext()
+		return m, fetchEpisodes(ctx, m.client, m.currentItem.ID)
+	case "allepisodes":
+		ctx := m.viewContext()
+		return m, fetchAllEpisodes(ctx, m.client, m.series.ID)
+	case "albums":
+		ctx := m.viewContext()
+		return m, fetchAlbums(ctx, m.client)
+	case "tracks":
+		ctx := m.viewContext()
+		return m, fetchTracks(ctx, m.client, m.currentItem.ID)
+	case "folder":
+		if len(m.folderStack) == 0 {
+			return m, nil
+		}
+		parentID := m.folderStack[len(m.folderStack)-1].parentID
+		ctx := m.viewContext()
+		if parentID == "" {
+			return m, fetchLibraries(ctx, m.client, m.config.HiddenLibraries)
+		}
+		return m, fetchChildren(ctx, m.client, parentID)
+	}
+	return m, nil
+}
+
+// Connection indicator styles
+var (
+	onlineStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	offlineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+)
+
+// connectionIndicator renders the connection state for the footer
+func (m Model) connectionIndicator() string {
+	switch m.connection {
+	case connectionOnline:
+		return onlineStyle.Render("● online")
+	case connectionOffline:
+		return offlineStyle.Render("● offline")
+	}
+	return ""
+}
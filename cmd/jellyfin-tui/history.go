@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxSearchHistory is how many recent searches are kept
+const maxSearchHistory = 20
+
+// loadSearchHistory loads recent searches, newest first, from
+// ~/.config/jellyfin-tui/search_history
+func loadSearchHistory() ([]string, error) {
+	configDir, err := appConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "search_history"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search history: %v", err)
+	}
+
+	var history []string
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse search history: %v", err)
+	}
+
+	if len(history) > maxSearchHistory {
+		history = history[:maxSearchHistory]
+	}
+	return history, nil
+}
+
+// saveSearchHistory saves recent searches to ~/.config/jellyfin-tui/search_history
+func saveSearchHistory(history []string) error {
+	configDir, err := appConfigDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal search history: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(configDir, "search_history"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write search history: %v", err)
+	}
+
+	return nil
+}
+
+// rememberSearch moves a query to the front of the history, dropping an
+// earlier copy that differs only in case and the oldest past the limit
+func rememberSearch(history []string, query string) []string {
+	updated := []string{query}
+	for _, previous := range history {
+		if !strings.EqualFold(previous, query) && len(updated) < maxSearchHistory {
+			updated = append(updated, previous)
+		}
+	}
+	return updated
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// maxInfoNames is how many names a line of the info view lists before
+// summing up the rest, e.g. "and 12 more"
+const maxInfoNames = 6
+
+// itemDetailsMsg carries the full metadata of an item
+type itemDetailsMsg struct {
+	item    MediaItem
+	details jellyfin.ItemDetails
+	err     error
+}
+
+// Info view styles
+var (
+	infoTitleStyle = lipgloss.NewStyle().Bold(true)
+	infoLabelStyle = lipgloss.NewStyle().Bold(true)
+	infoDimStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// openInfo shows the full details of an item, fetching them from the server
+// unless the details panel already has
+func (m Model) openInfo(item MediaItem) (Model, tea.Cmd) {
+	m.returnTo["info"] = m.currentView
+	m.currentView = "info"
+	m.infoItem, m.infoDetails = item, m.detailsCache[item.ID]
+	if m.infoDetails != nil {
+		return m, nil
+	}
+	ctx := m.viewContext()
+	return m, fetchItemDetails(ctx, m.client, item)
+}
+
+// setItemDetails keeps the details fetched for the info view or the details
+// panel, and shows them in the info view if it's still showing their item.
+// A failure shows in the panel while its item is highlighted; a fetch
+// cancelled by leaving the view is simply made again when the panel next
+// asks.
+func (m Model) setItemDetails(msg itemDetailsMsg) (Model, tea.Cmd) {
+	showing := m.currentView == "info" && msg.item.ID == m.infoItem.ID
+	if m.detailsPending == msg.item.ID {
+		m.detailsPending = ""
+	}
+	if errors.Is(msg.err, context.Canceled) {
+		return m, nil
+	}
+	if msg.err != nil {
+		if item, ok := m.highlightedItem(); ok && item.ID == msg.item.ID {
+			m.detailsFailed, m.detailsErr = msg.item.ID, msg.err
+		}
+		if showing {
+			m.status = fmt.Sprintf("Couldn't load details of %s: %v", msg.item.Title(), msg.err)
+		}
+		return m, nil
+	}
+	m.detailsCache[msg.item.ID] = &msg.details
+	if showing {
+		m.infoDetails = &msg.details
+	}
+	return m, nil
+}
+
+// updateInfo handles input in the info view: enter plays or opens the item
+func (m Model) updateInfo(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
+		return m.drillInto(m.infoItem)
+	}
+	return m, nil
+}
+
+// infoView renders the details of the item, wrapped to the window
+func (m Model) infoView() string {
+	width := m.availWidth
+	if width <= 0 {
+		width = 80
+	}
+	return renderDetails(m.infoItem, m.infoDetails, width) +
+		"\n\n" + infoDimStyle.Render("Enter to play or open, Esc to go back")
+}
+
+// renderDetails describes an item: its title, ratings and overview, then a
+// line each for its genres, studios, countries, directors and cast, leaving
+// out those it has none of
+func renderDetails(item MediaItem, details *jellyfin.ItemDetails, width int) string {
+	wrap := lipgloss.NewStyle().Width(width)
+	title := displayText(mediaTitle(item))
+	if details == nil {
+		return infoTitleStyle.Render(truncateText(title, width)) + "\n\n" + infoDimStyle.Render("Loading details...")
+	}
+
+	var facts []string
+	if details.OfficialRating != "" {
+		facts = append(facts, details.OfficialRating)
+	}
+	if details.CommunityRating > 0 {
+		facts = append(facts, fmt.Sprintf("★ %.1f", details.CommunityRating))
+	}
+	if details.RunTimeTicks > 0 {
+		facts = append(facts, formatDuration(details.RunTimeTicks))
+	}
+
+	sections := []string{infoTitleStyle.Render(wrap.Render(title))}
+	if len(facts) > 0 {
+		sections[0] += "\n" + infoDimStyle.Render(strings.Join(facts, " · "))
+	}
+	if len(details.Taglines) > 0 {
+		sections = append(sections, wrap.Italic(true).Render(displayText(details.Taglines[0])))
+	}
+	if overview := strings.TrimSpace(details.Overview); overview != "" {
+		sections = append(sections, wrap.Render(displayText(overview)))
+	}
+
+	var studios, directors, cast []string
+	for _, studio := range details.Studios {
+		studios = append(studios, studio.Name)
+	}
+	for _, person := range details.People {
+		switch {
+		case person.Type == "Director":
+			directors = append(directors, person.Name)
+		case person.Type == "Actor" && person.Role != "":
+			cast = append(cast, fmt.Sprintf("%s (%s)", person.Name, person.Role))
+		case person.Type == "Actor":
+			cast = append(cast, person.Name)
+		}
+	}
+
+	var lines []string
+	addLine := func(singular, plural string, names []string) {
+		if len(names) == 0 {
+			return
+		}
+		label := plural
+		if len(names) == 1 {
+			label = singular
+		}
+		lines = append(lines, wrap.Render(infoLabelStyle.Render(label+":")+" "+displayText(joinNames(names))))
+	}
+	addLine("Genre", "Genres", details.Genres)
+	addLine("Studio", "Studios", studios)
+	addLine("Country", "Countries", details.ProductionLocations)
+	addLine("Director", "Directors", directors)
+	addLine("Cast", "Cast", cast)
+	if len(lines) > 0 {
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+// joinNames lists names compactly: "A, B, C", or the first maxInfoNames and
+// how many more there are
+func joinNames(names []string) string {
+	if len(names) <= maxInfoNames {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:maxInfoNames], ", "), len(names)-maxInfoNames)
+}
+
+// Command to fetch the full metadata of an item
+func fetchItemDetails(ctx context.Context, client *jellyfin.Client, item MediaItem) tea.Cmd {
+	return func() tea.Msg {
+		details, err := client.GetItemDetails(ctx, item.ID)
+		if ctx.Err() != nil {
+			// Still reported, so the details panel knows to fetch them again
+			err = ctx.Err()
+		}
+		return itemDetailsMsg{item: item, details: details, err: err}
+	}
+}
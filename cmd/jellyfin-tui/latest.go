@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// latestLimit is how many recently added items are fetched
+const latestLimit = 50
+
+type fetchLatestMsg []MediaItem
+
+// latestTitle shows whether watched items are hidden
+func latestTitle(hideWatched bool) string {
+	if hideWatched {
+		return "Recently Added (unwatched only)"
+	}
+	return "Recently Added"
+}
+
+// openLatest shows the recently added view, using the configured filter
+func (m Model) openLatest() (Model, tea.Cmd) {
+	m.hideWatchedLatest = m.config.HideWatchedLatest
+	m.latestList.Title = latestTitle(m.hideWatchedLatest)
+	m.currentView = "latest"
+	ctx := m.viewContext()
+	return m, fetchLatest(ctx, m.client, m.hideWatchedLatest)
+}
+
+// updateLatest handles input in the recently added view
+func (m Model) updateLatest(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.latestList.FilterState() != list.Filtering {
+		switch keyMsg.String() {
+		case "w":
+			// Toggle hiding watched items for this visit
+			m.hideWatchedLatest = !m.hideWatchedLatest
+			m.latestList.Title = latestTitle(m.hideWatchedLatest)
+			ctx := m.viewContext()
+			return m, fetchLatest(ctx, m.client, m.hideWatchedLatest)
+		case "enter":
+			if selectedItem, ok := m.latestList.SelectedItem().(MediaItem); ok && selectedItem.ID != "" {
+				return m.drillInto(selectedItem)
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.latestList, cmd = m.latestList.Update(msg)
+	return m, cmd
+}
+
+// Command to fetch recently added items
+func fetchLatest(ctx context.Context, client *jellyfin.Client, hideWatched bool) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetLatest(ctx, latestLimit, hideWatched)
+		if err != nil {
+			return fetchError(err)
+		}
+		return fetchLatestMsg(convertItems(client, items))
+	}
+}
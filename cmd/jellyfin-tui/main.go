@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"sort"
+	"strings"
+	"syscall"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -17,8 +23,78 @@ import (
 
 // Config holds the Jellyfin server configuration
 type Config struct {
-	ServerURL string `json:"server_url"`
-	APIKey    string `json:"api_key"`
+	ServerURL      string `json:"server_url"`
+	APIKey         string `json:"api_key"`
+	RestoreSession bool   `json:"restore_session"` // Reopen the last view and selection on startup
+
+	SubtitleLanguage string `json:"subtitle_language,omitempty"` // Three-letter code used for subtitle searches, e.g. "eng"
+	CompactLists     bool   `json:"compact_lists"`               // Single-line list items
+	PrintPlay        bool   `json:"print_play"`                  // Show the player command instead of running it
+
+	UserID            string `json:"user_id,omitempty"`   // User for watched state; defaults to the first administrator
+	HideWatchedLatest bool   `json:"hide_watched_latest"` // Hide played items in Recently Added by default
+	DefaultView       string `json:"default_view"`        // "main" or "dashboard"
+	ShowAllMedia      bool   `json:"show_all_media"`      // Add an "All Media" entry listing movies and TV shows together
+	PrefetchLibraries bool   `json:"prefetch_libraries"`  // Fetch the movies, TV shows and music libraries at startup
+
+	HiddenLibraries []string `json:"hidden_libraries,omitempty"` // Names or IDs of libraries left out of Libraries
+
+	// Watched, unwatched, partially watched and favorite indicators
+	UnicodeSymbols  bool   `json:"unicode_symbols"` // Use ✓ ○ ◐ ★ instead of the ASCII defaults
+	WatchedSymbol   string `json:"watched_symbol,omitempty"`
+	UnwatchedSymbol string `json:"unwatched_symbol,omitempty"`
+	PartialSymbol   string `json:"partial_symbol,omitempty"`
+	FavoriteSymbol  string `json:"favorite_symbol,omitempty"`
+
+	// Colors of titles by watched state, as ANSI numbers ("241") or hex
+	// ("#888888"); "none" keeps the normal color. The highlighted item
+	// always uses the cursor's color.
+	WatchedColor    string `json:"watched_color,omitempty"`     // Defaults to "241", dim gray
+	InProgressColor string `json:"in_progress_color,omitempty"` // Defaults to "214", orange
+	UnwatchedColor  string `json:"unwatched_color,omitempty"`   // Defaults to the normal color
+
+	PreferredVersion string `json:"preferred_version,omitempty"` // "highest" or "lowest" bitrate, highlighted when a video has several versions
+
+	HealthCheckInterval int  `json:"health_check_interval,omitempty"` // Seconds between server pings; defaults to 30, negative disables
+	RefreshOnReconnect  bool `json:"refresh_on_reconnect"`            // Refetch the current view when the server comes back
+
+	StopPlayerOnExit  bool   `json:"stop_player_on_exit"`          // Close mpv when the app exits instead of leaving it playing
+	StreamContainer   string `json:"stream_container,omitempty"`   // Container videos are streamed in, e.g. "mkv"; empty lets the server decide
+	StreamingProtocol string `json:"streaming_protocol,omitempty"` // "direct" (the default) or "hls"
+	IdleTimeout       int    `json:"idle_timeout,omitempty"`       // Seconds without progress before reporting pauses; defaults to 300, negative never pauses
+
+	ProgressReportInterval   int  `json:"progress_report_interval,omitempty"` // Seconds between progress reports while playing; defaults to 10
+	DisableTranscodeFallback bool `json:"disable_transcode_fallback"`         // Don't retry videos mpv fails to play directly as a transcoded stream
+
+	ExtraHeaders          map[string]string `json:"extra_headers,omitempty"`           // Sent with every request, e.g. for an authenticating proxy
+	MaxConcurrentRequests int               `json:"max_concurrent_requests,omitempty"` // Requests sent to the server at once; defaults to 6, negative doesn't limit
+
+	// Bandwidth limits for videos, offered each time one is played; zero
+	// plays the original without asking
+	MaxStreamingBitrate int `json:"max_streaming_bitrate,omitempty"` // Bits per second, e.g. 4000000
+	MaxStreamingHeight  int `json:"max_streaming_height,omitempty"`  // e.g. 720
+
+	GroupSearchResults  bool `json:"group_search_results"`  // Group search results under a header per type
+	QuickPlaySearch     bool `json:"quick_play_search"`     // Play a search's only result straight away when its title is the query
+	UnwatchedShowsFirst bool `json:"unwatched_shows_first"` // List TV shows with unwatched episodes first
+
+	ConfirmUnwatch string `json:"confirm_unwatch,omitempty"` // Confirm marking unwatched: "bulk" (series, seasons and folders; the default), "always" or "never"
+
+	// Scrobble music played to Last.fm; needs an API account and a session key
+	Scrobble         bool   `json:"scrobble"`
+	LastFMAPIKey     string `json:"lastfm_api_key,omitempty"`
+	LastFMAPISecret  string `json:"lastfm_api_secret,omitempty"`
+	LastFMSessionKey string `json:"lastfm_session_key,omitempty"`
+
+	// Poster, overview and metadata of the highlighted item beside the list
+	// on terminals at least DetailsMinWidth wide, in place of the preview
+	ShowDetails     bool `json:"show_details"`
+	DetailsMinWidth int  `json:"details_min_width,omitempty"` // Cells; defaults to 100
+
+	// Image of the highlighted item beside the list
+	ShowPreview      bool `json:"show_preview"`
+	PreviewMaxWidth  int  `json:"preview_max_width,omitempty"`  // Cells; defaults to 30
+	PreviewMaxHeight int  `json:"preview_max_height,omitempty"` // Rows; defaults to 22
 }
 
 // MediaItem represents a movie or TV show
@@ -26,11 +102,28 @@ type MediaItem struct {
 	ID           string
 	ItemTitle    string
 	Type         string
-	ImageURL     string
+	ImageTag     string // Primary image tag, which changes with the artwork
 	StreamURL    string
 	ParentID     string
+	ItemType     string // Jellyfin item type, e.g. "Movie", "Series", "Folder"
+	IsFolder     bool   // Whether the item has children to browse
 	IndexNumber  int    // Add this field for episode numbers
 	DisplayTitle string // Add this for formatted display title
+	DisplayDesc  string // Formatted description line, e.g. track number and duration
+	UserData     jellyfin.UserData
+	SourceID     string // Version chosen to play, when the item has several
+	RunTimeTicks int64
+	Artist       string // Music tracks: the track's artist and album
+	Album        string
+	SeriesName   string // Episodes: the show and season number
+	SeasonNumber int
+	Year         int
+	Resolution   string // Videos: "4K", "1080p", "720p" or "SD", if known
+	Limited      bool   // Stream transcoded within the bandwidth limits
+
+	// Videos split across several files: the files after the first, in
+	// order, fetched when the video is played
+	Parts []MediaItem
 }
 
 // Implement the list.Item interface for MediaItem
@@ -41,23 +134,152 @@ func (m MediaItem) Title() string {
 	return m.ItemTitle
 }
 
-func (m MediaItem) Description() string { return m.Type }
+func (m MediaItem) Description() string {
+	desc := m.Type
+	if m.DisplayDesc != "" {
+		desc = m.DisplayDesc
+	}
+	if m.Resolution != "" {
+		desc = "[" + m.Resolution + "] " + desc
+	}
+	return desc
+}
+
 func (m MediaItem) FilterValue() string { return m.ItemTitle }
 
 // Model represents the application state
 type Model struct {
 	config       Config
-	currentView  string // "main", "movies", "tvshows", "seasons", "episodes", "search", "config"
+	client       *jellyfin.Client // Shared so concurrent identical fetches can be coalesced
+	player       *player          // Shared so playback can be wound down on exit
+	currentView  string           // "main", "dashboard", "movies", "tvshows", "seasons", "episodes", "allepisodes", "allmedia", "albums", "tracks", "genres", "artists", "musicalbums", "latest", "recent", "folder", "search", "subtitles", "versions", "sessions", "info", "config"
 	mainList     list.Model
 	moviesList   list.Model
 	tvShowsList  list.Model
+	tvShows      []MediaItem // In the server's order
 	seasonsList  list.Model
 	episodesList list.Model
+	albumsList   list.Model
+	tracksList   list.Model
+	latestList   list.Model
 	searchInput  textinput.Model
 	searchList   list.Model
+
+	// The query whose results are shown, its total matches, and whether a
+	// page of them is loading
+	searchQuery   string
+	searchTotal   int
+	searchLoading bool
+	searchItems   []MediaItem // Results loaded so far, in the server's order
+
+	// Recent searches, newest first, and the one recalled into the search
+	// box, or -1
+	searchHistory []string
+	historyIndex  int
+
 	configInputs []textinput.Model // Add this for config inputs
 	currentItem  MediaItem
+	series       MediaItem // Show whose seasons are listed
 	err          error
+	status       string // One-line message shown under the current view
+	printPlay    bool   // Set by config or --print-play
+
+	hideWatchedLatest bool // Current Recently Added filter, starting from the config default
+
+	// Every episode of a series under season headers
+	allEpisodesList list.Model
+
+	// Movies and TV shows together, newest first
+	allMediaList list.Model
+
+	// What was played most recently, latest first
+	recentList list.Model
+
+	// Music by genre and album artist
+	genresList        list.Model
+	artistsList       list.Model
+	musicAlbumsList   list.Model
+	musicAlbumsParent string // Genre or artist whose albums are listed
+
+	// Continue Watching, Next Up and Recently Added in one list
+	dashboardList     list.Model
+	dashboardSections [sectionCount][]MediaItem
+
+	// Generic library browsing: one list per folder level, and the view esc
+	// returns to from each drilled-into view
+	folderStack []folderLevel
+	returnTo    map[string]string
+	listWidth   int
+	listHeight  int
+
+	// Cancels fetches started for the current view when it's left
+	fetchCtx    context.Context
+	cancelFetch context.CancelFunc
+
+	// Subtitle search for the item highlighted when the view was opened
+	subtitlesList      list.Model
+	subtitleItem       MediaItem
+	subtitleReturnView string
+
+	// Versions of the item selected to play, when it has more than one
+	versionsList list.Model
+	versionItem  MediaItem
+
+	// Other devices the highlighted item can be played on
+	sessionsList list.Model
+	castItem     MediaItem
+
+	// The item whose details are shown, and its full metadata, nil while it
+	// loads
+	infoItem    MediaItem
+	infoDetails *jellyfin.ItemDetails
+
+	// Item IDs to highlight once the given view's fetch completes (session restore)
+	pendingSelect map[string]string
+
+	// Item highlighted in each view when it was last left, by positionKey,
+	// and the positionKey of what each view's list holds
+	positions map[string]string
+	listed    map[string]string
+
+	// Libraries fetched at startup, by view, until they're first opened
+	prefetch map[string]int
+
+	// Fetches for the view the app starts in
+	initCmd tea.Cmd
+
+	// Result of the last health check, shown in the footer
+	connection int
+
+	// Question waiting for a y/n answer on the status line
+	confirm *confirmation
+
+	// Offer to skip the intro or credits playing in mpv
+	skipPrompt  string
+	skipSegment func()
+
+	// Fetched movies and episodes, shown filtered by resolution
+	movieItems       []MediaItem
+	episodeItems     []MediaItem
+	resolutionFilter string
+
+	// Space for the lists and the preview, and the preview's share of it
+	availWidth    int
+	availHeight   int
+	previewWidth  int
+	previewHeight int
+	previewID     string      // Item whose image is shown
+	previewTag    string      // Tag of that image
+	previewCache  *imageCache // Rendered images by previewKey
+
+	// Width of the details panel, 0 while it's hidden; the details fetched
+	// so far, by item ID; the item whose details are being waited on; and
+	// the item whose details last failed to load, with why
+	detailsWidth   int
+	detailsCache   map[string]*jellyfin.ItemDetails
+	detailsPending string
+	detailsFailed  string
+	detailsErr     error
 }
 
 // Initialize the application
@@ -76,38 +298,94 @@ func initialModel() Model {
 
 	// Set up the main menu
 	mainItems := []list.Item{
+		MediaItem{ItemTitle: "Home", Type: "category"},
 		MediaItem{ItemTitle: "Movies", Type: "category"},
 		MediaItem{ItemTitle: "TV Shows", Type: "category"},
+	}
+	if config.ShowAllMedia {
+		mainItems = append(mainItems, MediaItem{ItemTitle: "All Media", Type: "category"})
+	}
+	mainItems = append(mainItems,
+		MediaItem{ItemTitle: "Music", Type: "category"},
+		MediaItem{ItemTitle: "Libraries", Type: "category"},
+		MediaItem{ItemTitle: "Recently Added", Type: "category"},
+		MediaItem{ItemTitle: "Recently Played", Type: "category"},
 		MediaItem{ItemTitle: "Search", Type: "action"},
 		MediaItem{ItemTitle: "Configure", Type: "action"},
-	}
+	)
 
-	mainList := list.New(mainItems, list.NewDefaultDelegate(), 0, 0)
+	delegate := newItemDelegate(config)
+	mainList := list.New(mainItems, delegate, 0, 0)
 	mainList.Title = "Jellyfin TUI"
 
 	// Set up empty lists for movies and TV shows
-	moviesList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	moviesList := list.New([]list.Item{}, delegate, 0, 0)
 	moviesList.Title = "Movies"
 
-	tvShowsList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	tvShowsList := list.New([]list.Item{}, delegate, 0, 0)
 	tvShowsList.Title = "TV Shows"
 
+	allMediaList := list.New([]list.Item{}, delegate, 0, 0)
+	allMediaList.Title = "All Media"
+
+	recentList := list.New([]list.Item{}, delegate, 0, 0)
+	recentList.Title = "Recently Played"
+
 	// Set up empty lists for seasons and episodes
-	seasonsList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	seasonsList := list.New([]list.Item{}, delegate, 0, 0)
 	seasonsList.Title = "Seasons"
 
-	episodesList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	episodesList := list.New([]list.Item{}, delegate, 0, 0)
 	episodesList.Title = "Episodes"
 
+	allEpisodesList := list.New([]list.Item{}, delegate, 0, 0)
+	allEpisodesList.Title = "All Episodes"
+
+	// Set up empty lists for music albums and tracks
+	albumsList := list.New([]list.Item{}, delegate, 0, 0)
+	albumsList.Title = "Music"
+
+	tracksList := list.New([]list.Item{}, delegate, 0, 0)
+	tracksList.Title = "Tracks"
+
+	genresList := list.New([]list.Item{}, delegate, 0, 0)
+	genresList.Title = "Genres"
+
+	artistsList := list.New([]list.Item{}, delegate, 0, 0)
+	artistsList.Title = "Album Artists"
+
+	musicAlbumsList := list.New([]list.Item{}, delegate, 0, 0)
+	musicAlbumsList.Title = "Albums"
+
+	// Set up empty list for recently added items
+	latestList := list.New([]list.Item{}, delegate, 0, 0)
+	latestList.Title = latestTitle(config.HideWatchedLatest)
+
+	// Set up empty dashboard list
+	dashboardList := list.New([]list.Item{}, delegate, 0, 0)
+	dashboardList.Title = "Home"
+
 	// Set up search input
 	searchInput := textinput.New()
 	searchInput.Placeholder = "Search for movies and TV shows..."
 	searchInput.Focus()
 
 	// Set up empty search results list
-	searchList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	searchList := list.New([]list.Item{}, delegate, 0, 0)
 	searchList.Title = "Search Results"
 
+	// Set up empty subtitle search results list
+	subtitlesList := list.New([]list.Item{}, delegate, 0, 0)
+	subtitlesList.Title = "Subtitles"
+
+	// Set up empty list of versions to play
+	versionsList := list.New([]list.Item{}, delegate, 0, 0)
+	versionsList.Title = "Versions"
+
+	// Set up empty list of other devices to play on
+	sessionsList := list.New([]list.Item{}, delegate, 0, 0)
+	sessionsList.Title = "Play on…"
+
 	// Set up config inputs
 	serverInput := textinput.New()
 	serverInput.Placeholder = "Jellyfin Server URL"
@@ -122,30 +400,79 @@ func initialModel() Model {
 
 	configInputs := []textinput.Model{serverInput, apiKeyInput}
 
-	return Model{
+	m := Model{
 		config:       config,
+		client:       newClient(config),
+		player:       newPlayer(config),
 		currentView:  "main",
 		mainList:     mainList,
 		moviesList:   moviesList,
 		tvShowsList:  tvShowsList,
 		seasonsList:  seasonsList,
 		episodesList: episodesList,
+		albumsList:   albumsList,
+		tracksList:   tracksList,
+		latestList:   latestList,
 		searchInput:  searchInput,
 		searchList:   searchList,
 		configInputs: configInputs,
+
+		allEpisodesList: allEpisodesList,
+		allMediaList:    allMediaList,
+		recentList:      recentList,
+		genresList:      genresList,
+		artistsList:     artistsList,
+		musicAlbumsList: musicAlbumsList,
+		dashboardList:   dashboardList,
+		subtitlesList:   subtitlesList,
+		versionsList:    versionsList,
+		sessionsList:    sessionsList,
+		returnTo:        map[string]string{},
+		previewCache:    newImageCache(previewCacheSize),
+		detailsCache:    map[string]*jellyfin.ItemDetails{},
+		positions:       map[string]string{},
+		listed:          map[string]string{},
+		prefetch:        map[string]int{},
+		historyIndex:    -1,
+	}
+	m.searchHistory, _ = loadSearchHistory() // None yet on first run
+
+	if config.RestoreSession {
+		if session, err := loadSession(); err == nil {
+			m.applySession(session)
+		}
+	}
+	if _, err := config.indicatorSymbols(); err != nil {
+		m.status = fmt.Sprintf("Config: %v", err)
+	}
+	if _, err := config.extraHeaders(); err != nil {
+		m.status = fmt.Sprintf("Config: %v", err)
+	}
+	if m.currentView == "main" && config.DefaultView == "dashboard" {
+		m, m.initCmd = m.openDashboard()
+	}
+	if config.PrefetchLibraries && config.ServerURL != "" {
+		m.initCmd = tea.Batch(m.initCmd, m.prefetchLibraries())
+	}
+
+	return m
+}
+
+// appConfigDir returns ~/.config/jellyfin-tui, where all local state is kept
+func appConfigDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
 	}
+	return filepath.Join(homeDir, ".config", "jellyfin-tui"), nil
 }
 
 // loadConfig loads the configuration from ~/.config/jellyfin-tui/config
 func loadConfig() (Config, error) {
-	// Get home directory
-	homeDir, err := os.UserHomeDir()
+	configDir, err := appConfigDir()
 	if err != nil {
-		return Config{}, fmt.Errorf("failed to get home directory: %v", err)
+		return Config{}, err
 	}
-
-	// Create config directory path
-	configDir := filepath.Join(homeDir, ".config", "jellyfin-tui")
 	configFile := filepath.Join(configDir, "config")
 
 	// Check if config file exists
@@ -172,15 +499,11 @@ func loadConfig() (Config, error) {
 
 // saveConfig saves the configuration to ~/.config/jellyfin-tui/config
 func saveConfig(config Config) error {
-	// Get home directory
-	homeDir, err := os.UserHomeDir()
+	configDir, err := appConfigDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %v", err)
+		return err
 	}
 
-	// Create config directory path
-	configDir := filepath.Join(homeDir, ".config", "jellyfin-tui")
-	
 	// Create directory if it doesn't exist
 	err = os.MkdirAll(configDir, 0755)
 	if err != nil {
@@ -210,28 +533,175 @@ type fetchMoviesMsg []MediaItem
 type fetchTVShowsMsg []MediaItem
 type fetchSeasonsMsg []MediaItem
 type fetchEpisodesMsg []MediaItem
-type searchResultsMsg []MediaItem
+type fetchAlbumsMsg []MediaItem
+type fetchTracksMsg []MediaItem
+type statusMsg string
 type errorMsg error
 
-// Update function handles all the application logic
+// Update handles a message, then loads the preview of whichever item is
+// now highlighted
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	updated, ok := next.(Model)
+	if !ok {
+		return next, cmd
+	}
+
+	m.rememberPosition(updated)
+	updated, previewCmd := updated.loadPreview()
+	updated, detailsCmd := updated.loadDetails()
+	return updated, tea.Batch(cmd, previewCmd, detailsCmd)
+}
+
+// update handles all the application logic
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// A pending question takes the next key press as its answer
+		if m.confirm != nil {
+			return m.updateConfirm(msg)
+		}
+
+		// Any key press dismisses the last status message
+		m.status = ""
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
+		case "v":
+			// Toggle between compact and detailed list items
+			if l := m.listForView(m.currentView); l != nil && !m.typingSearch() && l.FilterState() != list.Filtering {
+				m.setCompact(!m.config.CompactLists)
+				if err := saveConfig(m.config); err != nil {
+					m.status = fmt.Sprintf("Failed to save list preference: %v", err)
+				}
+				return m, nil
+			}
+		case "p":
+			// Show or hide the image preview beside the list
+			if l := m.listForView(m.currentView); l != nil && !m.typingSearch() && l.FilterState() != list.Filtering {
+				m.config.ShowPreview = !m.config.ShowPreview
+				m.layout()
+				if err := saveConfig(m.config); err != nil {
+					m.status = fmt.Sprintf("Failed to save preview preference: %v", err)
+				}
+				return m, nil
+			}
+		case "D":
+			// Show or hide the details panel beside the list
+			if l := m.listForView(m.currentView); l != nil && !m.typingSearch() && l.FilterState() != list.Filtering {
+				return m.toggleDetails()
+			}
+		case "o":
+			// Open the highlighted item in the Jellyfin web client
+			if l := m.listForView(m.currentView); l != nil && !m.typingSearch() && l.FilterState() != list.Filtering {
+				if item, ok := l.SelectedItem().(MediaItem); ok && item.ID != "" {
+					return m, openInBrowser(m.client.GetWebURL(item.ID))
+				}
+			}
+		case "F":
+			// Cycle through showing only movies and episodes of one resolution
+			if m.currentView == "movies" || m.currentView == "episodes" {
+				if l := m.listForView(m.currentView); l.FilterState() != list.Filtering {
+					m.resolutionFilter = nextResolutionFilter(m.resolutionFilter)
+					m.applyResolutionFilter()
+					return m, nil
+				}
+			}
+		case "R":
+			// Ask the server to refresh the highlighted item's metadata
+			if l := m.listForView(m.currentView); l != nil && !m.typingSearch() && l.FilterState() != list.Filtering {
+				if item, ok := l.SelectedItem().(MediaItem); ok && item.ID != "" {
+					return m.askConfirm(fmt.Sprintf("Refresh metadata for %s?", item.Title()), refreshMetadata(m.client, item))
+				}
+			}
+		case "m":
+			// Mark the highlighted item watched or unwatched
+			if l := m.listForView(m.currentView); l != nil && !m.typingSearch() && l.FilterState() != list.Filtering {
+				if item, ok := l.SelectedItem().(MediaItem); ok && item.ID != "" {
+					return m.toggleWatched(item)
+				}
+			}
+		case "ctrl+r":
+			// Reload the current view, starting again at the top
+			if l := m.listForView(m.currentView); l != nil && l.FilterState() != list.Filtering {
+				return m.forceRefresh()
+			}
+		case "i":
+			// Skip the intro or credits playing in mpv
+			if l := m.listForView(m.currentView); m.skipSegment != nil && m.currentView != "config" && !m.typingSearch() &&
+				(l == nil || l.FilterState() != list.Filtering) {
+				skip := m.skipSegment
+				m.skipPrompt, m.skipSegment = "", nil
+				return m, func() tea.Msg {
+					skip()
+					return nil
+				}
+			}
+		case "z", "Z":
+			// Cycle the subtitles of what's playing in mpv, or show or hide them
+			if l := m.listForView(m.currentView); m.currentView != "config" && !m.typingSearch() &&
+				(l == nil || l.FilterState() != list.Filtering) {
+				action := cycleSubtitles
+				if msg.String() == "Z" {
+					action = toggleSubtitleShown
+				}
+				return m, m.player.changeSubtitles(action)
+			}
+		case "c":
+			// Play the highlighted item on another device instead of in mpv
+			if l := m.listForView(m.currentView); l != nil && !m.typingSearch() && l.FilterState() != list.Filtering {
+				if item, ok := l.SelectedItem().(MediaItem); ok && item.ID != "" && !item.IsFolder && item.StreamURL != "" {
+					return m.openSessions(item)
+				}
+			}
+		case "I":
+			// Show the full details of the highlighted item
+			if l := m.listForView(m.currentView); l != nil && !m.typingSearch() && l.FilterState() != list.Filtering {
+				if item, ok := l.SelectedItem().(MediaItem); ok && item.ID != "" {
+					return m.openInfo(item)
+				}
+			}
+		case "s":
+			// Search for subtitles for the highlighted movie or episode
+			if m.currentView == "movies" || m.currentView == "episodes" {
+				l := m.listForView(m.currentView)
+				if item, ok := l.SelectedItem().(MediaItem); ok && item.ID != "" && l.FilterState() != list.Filtering {
+					return m.openSubtitles(item)
+				}
+			}
 		case "esc":
-			// Handle navigation back up the hierarchy
+			// Handle navigation back up the hierarchy, abandoning fetches for
+			// the view being left
+			m.cancelFetches()
+			if m.currentView == "folder" {
+				return m.popFolder(), nil
+			}
+			if m.currentView == "search" && !m.searchInput.Focused() {
+				// Back from the results to the search box
+				m.searchInput.Focus()
+				return m, nil
+			}
+			if prev, ok := m.returnTo[m.currentView]; ok {
+				m.currentView = prev
+				return m, nil
+			}
 			switch m.currentView {
-			case "episodes":
+			case "subtitles":
+				m.currentView = m.subtitleReturnView
+				return m, nil
+			case "episodes", "allepisodes":
 				m.currentView = "seasons"
 				return m, nil
 			case "seasons":
 				m.currentView = "tvshows"
 				return m, nil
-			case "movies", "tvshows", "search":
+			case "tracks":
+				m.currentView = "albums"
+				return m, nil
+			case "dashboard", "movies", "tvshows", "albums", "latest", "search":
 				m.currentView = "main"
 				return m, nil
 			}
@@ -239,31 +709,169 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.WindowSizeMsg:
 		h, v := lipgloss.NewStyle().Margin(1, 2).GetFrameSize()
-		m.mainList.SetSize(msg.Width-h, msg.Height-v)
-		m.moviesList.SetSize(msg.Width-h, msg.Height-v)
-		m.tvShowsList.SetSize(msg.Width-h, msg.Height-v)
-		m.seasonsList.SetSize(msg.Width-h, msg.Height-v)
-		m.episodesList.SetSize(msg.Width-h, msg.Height-v)
-		m.searchList.SetSize(msg.Width-h, msg.Height-v)
+		v++ // Leave a line for the footer
+		m.availWidth, m.availHeight = msg.Width-h, msg.Height-v
+		m.layout()
 
 	case fetchMoviesMsg:
-		m.moviesList.SetItems(convertToListItems(msg))
+		m.movieItems = msg
+		m.moviesList.SetItems(filterByResolution(msg, m.resolutionFilter))
+		m.restorePosition("movies", &m.moviesList)
 		return m, nil
 
 	case fetchTVShowsMsg:
-		m.tvShowsList.SetItems(convertToListItems(msg))
+		m.setTVShows(msg)
+		m.restorePosition("tvshows", &m.tvShowsList)
 		return m, nil
 
 	case fetchSeasonsMsg:
 		m.seasonsList.SetItems(convertToListItems(msg))
+		m.restorePosition("seasons", &m.seasonsList)
 		return m, nil
 
 	case fetchEpisodesMsg:
-		m.episodesList.SetItems(convertToListItems(msg))
+		m.episodeItems = msg
+		m.episodesList.SetItems(filterByResolution(msg, m.resolutionFilter))
+		m.restorePosition("episodes", &m.episodesList)
+		return m, nil
+
+	case fetchAlbumsMsg:
+		m.albumsList.SetItems(convertToListItems(msg))
+		m.restorePosition("albums", &m.albumsList)
+		return m, nil
+
+	case fetchTracksMsg:
+		m.tracksList.SetItems(convertToListItems(msg))
+		m.restorePosition("tracks", &m.tracksList)
 		return m, nil
 
 	case searchResultsMsg:
-		m.searchList.SetItems(convertToListItems(msg))
+		return m.setSearchResults(msg)
+
+	case itemDetailsMsg:
+		return m.setItemDetails(msg)
+
+	case subtitleResultsMsg:
+		items := make([]list.Item, len(msg))
+		for i, subtitle := range msg {
+			items[i] = SubtitleItem{subtitle}
+		}
+		m.subtitlesList.SetItems(items)
+		if len(msg) == 0 {
+			m.status = fmt.Sprintf("No %s subtitles found", m.config.subtitleLanguage())
+		} else {
+			m.status = ""
+		}
+		return m, nil
+
+	case subtitleDownloadedMsg:
+		if m.currentView == "subtitles" && m.subtitleReturnView == "versions" {
+			// Back to choosing a version, to play it with the new subtitle
+			m.currentView = "versions"
+			m.status = "Subtitle downloaded; press Enter to play with it"
+			return m, nil
+		}
+		m.status = "Subtitle downloaded; it will be available the next time you play this item"
+		return m, nil
+
+	case fetchGenresMsg:
+		m.genresList.SetItems(convertToListItems(msg))
+		m.restorePosition("genres", &m.genresList)
+		return m, nil
+
+	case fetchArtistsMsg:
+		m.artistsList.SetItems(convertToListItems(msg))
+		m.restorePosition("artists", &m.artistsList)
+		return m, nil
+
+	case fetchMusicAlbumsMsg:
+		m.musicAlbumsList.SetItems(msg)
+		m.restorePosition("musicalbums", &m.musicAlbumsList)
+		skipHeaders(&m.musicAlbumsList, false)
+		return m, nil
+
+	case fetchAllEpisodesMsg:
+		m.allEpisodesList.SetItems(msg)
+		m.restorePosition("allepisodes", &m.allEpisodesList)
+		skipHeaders(&m.allEpisodesList, false)
+		return m, nil
+
+	case dashboardSectionMsg:
+		m.setDashboardSection(msg)
+		return m, nil
+
+	case fetchRecentMsg:
+		m.recentList.SetItems(convertToListItems(msg))
+		m.restorePosition("recent", &m.recentList)
+		return m, nil
+
+	case playMsg:
+		return m.startPlayback(MediaItem(msg))
+
+	case fetchAllMediaMsg:
+		m.allMediaList.SetItems(convertToListItems(msg))
+		m.restorePosition("allmedia", &m.allMediaList)
+		return m, nil
+
+	case fetchLatestMsg:
+		m.latestList.SetItems(convertToListItems(msg))
+		m.restorePosition("latest", &m.latestList)
+		return m, nil
+
+	case fetchChildrenMsg:
+		m.setFolderItems(msg)
+		return m, nil
+
+	case mediaSourcesMsg:
+		return m.chooseSource(msg)
+
+	case fetchSessionsMsg:
+		return m.setSessions(msg)
+
+	case skipPromptMsg:
+		m.skipPrompt, m.skipSegment = msg.prompt, msg.skip
+		return m, m.player.listen()
+
+	case subtitleStatusMsg:
+		m.status = string(msg)
+		return m, m.player.listen()
+
+	case transcodeFallbackMsg:
+		transcoded := msg.item
+		transcoded.Limited = true
+		m.status = fmt.Sprintf("MPV couldn't play %s directly (%v); retrying as a transcoded stream", msg.item.Title(), msg.err)
+		return m, tea.Batch(m.playItem(transcoded), m.player.listen())
+
+	case unwatchCountMsg:
+		return m.confirmBulkUnwatch(msg)
+
+	case playedMsg:
+		return m.updatePlayed(msg)
+
+	case statusMsg:
+		m.status = string(msg)
+		return m, nil
+
+	case prefetchedMsg:
+		return m.setPrefetched(msg)
+
+	case previewMsg:
+		m.previewCache.put(msg.key, msg.image)
+		return m, nil
+
+	case detailsDueMsg:
+		return m.fetchRestingDetails(msg)
+
+	case healthTickMsg:
+		return m, pingServer(m.client, m.config.healthCheckInterval())
+
+	case healthResultMsg:
+		return m.updateHealth(msg)
+
+	case restoreFailedMsg:
+		// The restored view's parent is gone or unreachable; start fresh
+		m.currentView = "main"
+		m.pendingSelect = nil
 		return m, nil
 
 	case errorMsg:
@@ -281,16 +889,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			selectedItem, ok := m.mainList.SelectedItem().(MediaItem)
 			if ok {
 				switch selectedItem.ItemTitle {
+				case "Home":
+					return m.openDashboard()
 				case "Movies":
-					m.currentView = "movies"
-					return m, fetchMovies(m.config)
+					return m.openLibrary("movies")
 				case "TV Shows":
-					m.currentView = "tvshows"
-					return m, fetchTVShows(m.config)
+					return m.openLibrary("tvshows")
+				case "All Media":
+					return m.openAllMedia()
+				case "Music":
+					return m.openLibrary("albums")
+				case "Libraries":
+					return m.openLibraries()
+				case "Recently Added":
+					return m.openLatest()
+				case "Recently Played":
+					return m.openRecent()
 				case "Search":
-					m.currentView = "search"
-					m.searchInput.SetValue("")
-					return m, nil
+					return m.openSearch()
 				case "Configure":
 					m.currentView = "config"
 					m.configInputs[0].SetValue(m.config.ServerURL)
@@ -302,6 +918,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case "movies", "tvshows":
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "u" && m.currentView == "tvshows" && m.tvShowsList.FilterState() != list.Filtering {
+			return m.toggleShowSort(), nil
+		}
+
 		var list *list.Model
 		if m.currentView == "movies" {
 			list = &m.moviesList
@@ -315,9 +935,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
 			selectedItem, ok := list.SelectedItem().(MediaItem)
 			if ok && selectedItem.ID != "" {
-				m.currentItem = selectedItem
-				m.currentView = "seasons"
-				return m, fetchSeasons(m.config, selectedItem.ID)
+				return m.drillInto(selectedItem)
 			}
 		}
 
@@ -328,12 +946,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
 			selectedItem, ok := m.seasonsList.SelectedItem().(MediaItem)
 			if ok && selectedItem.ID != "" {
-				m.currentItem = selectedItem
-				m.currentView = "episodes"
-				return m, fetchEpisodes(m.config, selectedItem.ID)
+				return m.drillInto(selectedItem)
 			}
 		}
 
+		// Show every episode of the series, grouped by season
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "a" && m.seasonsList.FilterState() != list.Filtering {
+			return m.openAllEpisodes(m.series)
+		}
+
 	case "episodes":
 		m.episodesList, cmd = m.episodesList.Update(msg)
 		
@@ -341,33 +962,81 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
 			selectedItem, ok := m.episodesList.SelectedItem().(MediaItem)
 			if ok && selectedItem.ID != "" {
-				return m, playMedia(selectedItem)
+				return m.drillInto(selectedItem)
 			}
 		}
 
-	case "search":
+	case "albums":
+		m.albumsList, cmd = m.albumsList.Update(msg)
+
+		// Handle selection of an album
 		if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
-			query := m.searchInput.Value()
-			if query != "" {
-				return m, searchMedia(m.config, query)
+			selectedItem, ok := m.albumsList.SelectedItem().(MediaItem)
+			if ok && selectedItem.ID != "" {
+				return m.drillInto(selectedItem)
 			}
-		} else {
-			m.searchInput, cmd = m.searchInput.Update(msg)
 		}
 
-		// If we're viewing search results
-		if len(m.searchList.Items()) > 0 {
-			m.searchList, cmd = m.searchList.Update(msg)
-			
-			// Handle selection of a search result
-			if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
-				selectedItem, ok := m.searchList.SelectedItem().(MediaItem)
-				if ok && selectedItem.ID != "" {
-					return m, playMedia(selectedItem)
-				}
+		// Browse by genre or album artist
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && m.albumsList.FilterState() != list.Filtering {
+			switch keyMsg.String() {
+			case "g":
+				return m.openGenres()
+			case "A":
+				return m.openArtists()
 			}
 		}
 
+	case "tracks":
+		m.tracksList, cmd = m.tracksList.Update(msg)
+
+		// Handle selection of a track
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
+			selectedItem, ok := m.tracksList.SelectedItem().(MediaItem)
+			if ok && selectedItem.ID != "" {
+				return m.drillInto(selectedItem)
+			}
+		}
+
+	case "search":
+		m, cmd = m.updateSearch(msg)
+
+	case "dashboard":
+		m, cmd = m.updateDashboard(msg)
+
+	case "allepisodes":
+		m, cmd = m.updateAllEpisodes(msg)
+
+	case "allmedia":
+		m, cmd = m.updateAllMedia(msg)
+
+	case "recent":
+		m, cmd = m.updateRecent(msg)
+
+	case "genres", "artists":
+		m, cmd = m.updateMusicIndex(msg)
+
+	case "musicalbums":
+		m, cmd = m.updateMusicAlbums(msg)
+
+	case "latest":
+		m, cmd = m.updateLatest(msg)
+
+	case "folder":
+		m, cmd = m.updateFolder(msg)
+
+	case "subtitles":
+		m, cmd = m.updateSubtitles(msg)
+
+	case "versions":
+		m, cmd = m.updateVersions(msg)
+
+	case "sessions":
+		m, cmd = m.updateSessions(msg)
+
+	case "info":
+		m, cmd = m.updateInfo(msg)
+
 	case "config":
 		// Handle tab to switch between inputs
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
@@ -395,11 +1064,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 				
 			case "enter":
-				// Save config
-				newConfig := Config{
-					ServerURL: m.configInputs[0].Value(),
-					APIKey:    m.configInputs[1].Value(),
-				}
+				// Save config, keeping settings that aren't edited here
+				newConfig := m.config
+				newConfig.ServerURL = m.configInputs[0].Value()
+				newConfig.APIKey = m.configInputs[1].Value()
 				
 				err := saveConfig(newConfig)
 				if err != nil {
@@ -408,6 +1076,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				
 				m.config = newConfig
+				m.client = newClient(newConfig)
 				m.currentView = "main"
 				return m, nil
 			}
@@ -431,6 +1100,44 @@ func (m Model) View() string {
 		return fmt.Sprintf("Error: %v\n\nPress any key to exit.", m.err)
 	}
 
+	view := m.viewContent()
+	if m.listForView(m.currentView) != nil {
+		side := m.previewView()
+		if m.detailsWidth > 0 {
+			side = m.detailsView()
+		}
+		if side != "" {
+			view = lipgloss.JoinHorizontal(lipgloss.Top,
+				lipgloss.NewStyle().Width(m.listWidth+previewGap).Render(view), side)
+		}
+	}
+
+	var footer []string
+	if indicator := m.connectionIndicator(); indicator != "" {
+		footer = append(footer, indicator)
+	}
+	if m.skipPrompt != "" {
+		footer = append(footer, skipPromptStyle.Render(m.skipPrompt))
+	}
+	if m.status != "" {
+		footer = append(footer, statusStyle.Render(m.status))
+	}
+	if len(footer) > 0 {
+		view += "\n" + footerStyle.Render(strings.Join(footer, "  "))
+	}
+	return view
+}
+
+// The footer under the current view: the connection indicator, the skip
+// prompt and the status message
+var (
+	footerStyle     = lipgloss.NewStyle().PaddingLeft(2)
+	skipPromptStyle = lipgloss.NewStyle().Bold(true)
+	statusStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// viewContent renders the current view
+func (m Model) viewContent() string {
 	switch m.currentView {
 	case "main":
 		return m.mainList.View()
@@ -442,14 +1149,44 @@ func (m Model) View() string {
 		return m.seasonsList.View()
 	case "episodes":
 		return m.episodesList.View()
+	case "albums":
+		return m.albumsList.View()
+	case "tracks":
+		return m.tracksList.View()
+	case "genres":
+		return m.genresList.View()
+	case "artists":
+		return m.artistsList.View()
+	case "musicalbums":
+		return m.musicAlbumsList.View()
+	case "dashboard":
+		return m.dashboardList.View()
+	case "allepisodes":
+		return m.allEpisodesList.View()
+	case "allmedia":
+		return m.allMediaList.View()
+	case "recent":
+		return m.recentList.View()
+	case "latest":
+		return m.latestList.View()
+	case "folder":
+		if l := m.listForView("folder"); l != nil {
+			return l.View()
+		}
+		return ""
+	case "subtitles":
+		return m.subtitlesList.View()
+	case "versions":
+		return m.versionsList.View()
+	case "sessions":
+		return m.sessionsList.View()
+	case "info":
+		return m.infoView()
 	case "search":
-		if len(m.searchList.Items()) > 0 {
+		if !m.searchInput.Focused() && len(m.searchList.Items()) > 0 {
 			return m.searchList.View()
 		}
-		return fmt.Sprintf(
-			"Search: %s\n\nType a search query and press Enter",
-			m.searchInput.View(),
-		)
+		return m.searchBoxView()
 	case "config":
 		return fmt.Sprintf(
 			"Configure Jellyfin Connection\n\n"+
@@ -464,6 +1201,80 @@ func (m Model) View() string {
 	}
 }
 
+// allLists returns every list in the model, for changes that apply to all views
+func (m *Model) allLists() []*list.Model {
+	lists := []*list.Model{
+		&m.mainList, &m.moviesList, &m.tvShowsList, &m.seasonsList, &m.episodesList,
+		&m.albumsList, &m.tracksList, &m.genresList, &m.artistsList, &m.musicAlbumsList, &m.latestList, &m.dashboardList, &m.allEpisodesList, &m.allMediaList, &m.recentList,
+		&m.searchList, &m.subtitlesList, &m.versionsList, &m.sessionsList,
+	}
+	for i := range m.folderStack {
+		lists = append(lists, &m.folderStack[i].list)
+	}
+	return lists
+}
+
+// viewContext cancels fetches still running for the previous view and
+// returns the context for the new view's fetches
+func (m *Model) viewContext() context.Context {
+	m.cancelFetches()
+	m.fetchCtx, m.cancelFetch = context.WithCancel(context.Background())
+	return m.fetchCtx
+}
+
+// cancelFetches cancels fetches started for the current view
+func (m *Model) cancelFetches() {
+	if m.cancelFetch != nil {
+		m.cancelFetch()
+		m.cancelFetch = nil
+	}
+}
+
+// fetchError reports a failed fetch, ignoring fetches that were cancelled
+// because the user navigated away. Content the user isn't allowed to see is
+// reported on the status line rather than as a fatal error.
+func fetchError(err error) tea.Msg {
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+
+	var statusErr *jellyfin.StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusUnauthorized:
+			return statusMsg("The server rejected the API key; update it under Configure")
+		case http.StatusForbidden:
+			return statusMsg("This library is locked: parental controls or library access settings block it for this user")
+		}
+	}
+	return errorMsg(err)
+}
+
+// newClient creates the Jellyfin client for a config
+func newClient(config Config) *jellyfin.Client {
+	client := jellyfin.NewClient(config.ServerURL, config.APIKey)
+	client.UserID = config.UserID
+	client.Headers, _ = config.extraHeaders()
+	client.SetMaxConcurrency(config.maxConcurrentRequests())
+	return client
+}
+
+// defaultMaxConcurrentRequests is how many requests are sent to the server
+// at once unless configured otherwise
+const defaultMaxConcurrentRequests = 6
+
+// maxConcurrentRequests returns how many requests may be sent to the server
+// at once, or 0 for no limit
+func (c Config) maxConcurrentRequests() int {
+	switch {
+	case c.MaxConcurrentRequests < 0:
+		return 0
+	case c.MaxConcurrentRequests == 0:
+		return defaultMaxConcurrentRequests
+	}
+	return c.MaxConcurrentRequests
+}
+
 // Helper function to convert MediaItems to list.Items
 func convertToListItems(items []MediaItem) []list.Item {
 	listItems := make([]list.Item, len(items))
@@ -474,12 +1285,11 @@ func convertToListItems(items []MediaItem) []list.Item {
 }
 
 // Command to fetch movies from Jellyfin
-func fetchMovies(config Config) tea.Cmd {
+func fetchMovies(ctx context.Context, client *jellyfin.Client) tea.Cmd {
 	return func() tea.Msg {
-		client := jellyfin.NewClient(config.ServerURL, config.APIKey)
-		items, err := client.GetMovies()
+		items, err := client.GetMovies(ctx)
 		if err != nil {
-			return errorMsg(err)
+			return fetchError(err)
 		}
 		
 		// Convert jellyfin.MediaItem to our MediaItem
@@ -488,9 +1298,15 @@ func fetchMovies(config Config) tea.Cmd {
 			mediaItems[i] = MediaItem{
 				ID:        item.ID,
 				ItemTitle: item.Name,
+				ItemType:  item.Type,
+				IsFolder:  item.IsFolder,
+				UserData:  item.UserData,
+				ImageTag:  item.ImageTags["Primary"],
 				Type:      item.MediaType,
 				// You can construct image URL if needed
-				StreamURL: client.GetStreamURL(item.ID),
+				StreamURL:  client.GetStreamURL(item.ID),
+				Resolution: resolutionBadge(item.Width, item.Height),
+				Year:       item.ProductionYear,
 			}
 		}
 		
@@ -499,12 +1315,11 @@ func fetchMovies(config Config) tea.Cmd {
 }
 
 // Command to fetch TV shows from Jellyfin
-func fetchTVShows(config Config) tea.Cmd {
+func fetchTVShows(ctx context.Context, client *jellyfin.Client) tea.Cmd {
 	return func() tea.Msg {
-		client := jellyfin.NewClient(config.ServerURL, config.APIKey)
-		items, err := client.GetTVShows()
+		items, err := client.GetTVShows(ctx)
 		if err != nil {
-			return errorMsg(err)
+			return fetchError(err)
 		}
 		
 		// Convert jellyfin.MediaItem to our MediaItem
@@ -513,6 +1328,10 @@ func fetchTVShows(config Config) tea.Cmd {
 			mediaItems[i] = MediaItem{
 				ID:        item.ID,
 				ItemTitle: item.Name,
+				ItemType:  item.Type,
+				IsFolder:  item.IsFolder,
+				UserData:  item.UserData,
+				ImageTag:  item.ImageTags["Primary"],
 				Type:      "tvshow",
 				// You can construct image URL if needed
 				StreamURL: client.GetStreamURL(item.ID),
@@ -524,15 +1343,11 @@ func fetchTVShows(config Config) tea.Cmd {
 }
 
 // Command to fetch seasons for a TV show
-func fetchSeasons(config Config, seriesID string) tea.Cmd {
+func fetchSeasons(ctx context.Context, client *jellyfin.Client, seriesID string) tea.Cmd {
 	return func() tea.Msg {
-		client := jellyfin.NewClient(config.ServerURL, config.APIKey)
-		endpoint := fmt.Sprintf("%s/Shows/%s/Seasons?api_key=%s", 
-			config.ServerURL, seriesID, config.APIKey)
-		
-		items, err := client.FetchItems(endpoint)
+		items, err := client.GetSeasons(ctx, seriesID)
 		if err != nil {
-			return errorMsg(err)
+			return fetchError(err)
 		}
 		
 		// Convert jellyfin.MediaItem to our MediaItem
@@ -541,6 +1356,10 @@ func fetchSeasons(config Config, seriesID string) tea.Cmd {
 			mediaItems[i] = MediaItem{
 				ID:        item.ID,
 				ItemTitle: item.Name,
+				ItemType:  item.Type,
+				IsFolder:  item.IsFolder,
+				UserData:  item.UserData,
+				ImageTag:  item.ImageTags["Primary"],
 				Type:      "season",
 				ParentID:  seriesID,
 				StreamURL: "",
@@ -552,17 +1371,11 @@ func fetchSeasons(config Config, seriesID string) tea.Cmd {
 }
 
 // Command to fetch episodes for a season
-func fetchEpisodes(config Config, seasonID string) tea.Cmd {
+func fetchEpisodes(ctx context.Context, client *jellyfin.Client, seasonID string) tea.Cmd {
 	return func() tea.Msg {
-		client := jellyfin.NewClient(config.ServerURL, config.APIKey)
-		
-		// Fix the endpoint URL format - this is the correct Jellyfin API path
-		endpoint := fmt.Sprintf("%s/Items?ParentId=%s&api_key=%s&SortBy=SortName", 
-			config.ServerURL, seasonID, config.APIKey)
-		
-		items, err := client.FetchItems(endpoint)
+		items, err := client.GetEpisodes(ctx, seasonID)
 		if err != nil {
-			return errorMsg(err)
+			return fetchError(err)
 		}
 		
 		// Convert jellyfin.MediaItem to our MediaItem
@@ -577,11 +1390,18 @@ func fetchEpisodes(config Config, seasonID string) tea.Cmd {
 			mediaItems[i] = MediaItem{
 				ID:           item.ID,
 				ItemTitle:    item.Name,
+				ItemType:     item.Type,
+				IsFolder:     item.IsFolder,
+				UserData:     item.UserData,
+				ImageTag:     item.ImageTags["Primary"],
 				Type:         "episode",
 				ParentID:     seasonID,
 				StreamURL:    client.GetStreamURL(item.ID),
 				IndexNumber:  item.IndexNumber,
 				DisplayTitle: displayTitle,
+				Resolution:   resolutionBadge(item.Width, item.Height),
+				SeriesName:   item.SeriesName,
+				SeasonNumber: item.ParentIndexNumber,
 			}
 		}
 		
@@ -594,56 +1414,248 @@ func fetchEpisodes(config Config, seasonID string) tea.Cmd {
 	}
 }
 
-// Command to search for media
-func searchMedia(config Config, query string) tea.Cmd {
+// Command to fetch music albums from Jellyfin
+func fetchAlbums(ctx context.Context, client *jellyfin.Client) tea.Cmd {
 	return func() tea.Msg {
-		client := jellyfin.NewClient(config.ServerURL, config.APIKey)
-		items, err := client.Search(query)
+		items, err := client.GetMusicAlbums(ctx)
 		if err != nil {
-			return errorMsg(err)
+			return fetchError(err)
 		}
-		
+		return fetchAlbumsMsg(convertAlbums(client, items))
+	}
+}
+
+// convertAlbums converts music albums for the album lists
+func convertAlbums(client *jellyfin.Client, items []jellyfin.MediaItem) []MediaItem {
+	mediaItems := make([]MediaItem, len(items))
+	for i, item := range items {
+		mediaItems[i] = MediaItem{
+			ID:          item.ID,
+			ItemTitle:   item.Name,
+			ItemType:    item.Type,
+			IsFolder:    item.IsFolder,
+			UserData:    item.UserData,
+			Type:        "album",
+			ImageTag:    item.ImageTags["Primary"],
+			DisplayDesc: formatAlbumDescription(item),
+		}
+	}
+	return mediaItems
+}
+
+// Command to fetch the tracks of a music album
+func fetchTracks(ctx context.Context, client *jellyfin.Client, albumID string) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetAlbumTracks(ctx, albumID)
+		if err != nil {
+			return fetchError(err)
+		}
+
 		// Convert jellyfin.MediaItem to our MediaItem
 		mediaItems := make([]MediaItem, len(items))
 		for i, item := range items {
+			displayTitle := item.Name
+			if item.IndexNumber > 0 {
+				displayTitle = fmt.Sprintf("%02d. %s", item.IndexNumber, item.Name)
+			}
+
 			mediaItems[i] = MediaItem{
-				ID:        item.ID,
-				ItemTitle: item.Name,
-				Type:      item.MediaType,
-				// You can construct image URL if needed
-				StreamURL: client.GetStreamURL(item.ID),
+				ID:           item.ID,
+				ItemTitle:    item.Name,
+				ItemType:     item.Type,
+				IsFolder:     item.IsFolder,
+				UserData:     item.UserData,
+				Type:         "track",
+				ImageTag:     item.AlbumImageTag,
+				StreamURL:    client.GetAudioStreamURL(item.ID),
+				ParentID:     albumID,
+				IndexNumber:  item.IndexNumber,
+				DisplayTitle: displayTitle,
+				DisplayDesc:  formatTrackDescription(item),
+				RunTimeTicks: item.RunTimeTicks,
+				Artist:       trackArtist(item),
+				Album:        item.Album,
 			}
 		}
-		
-		return searchResultsMsg(mediaItems)
+
+		return fetchTracksMsg(mediaItems)
+	}
+}
+
+// formatTrackDescription builds the description line for an audio track,
+// e.g. "Track 3 · 4:12 · Album Name"
+func formatTrackDescription(item jellyfin.MediaItem) string {
+	var parts []string
+	if item.IndexNumber > 0 {
+		parts = append(parts, fmt.Sprintf("Track %d", item.IndexNumber))
+	}
+	if item.RunTimeTicks > 0 {
+		parts = append(parts, formatDuration(item.RunTimeTicks))
+	}
+	if item.Album != "" {
+		parts = append(parts, item.Album)
+	}
+	if len(parts) == 0 {
+		return "track"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatAlbumDescription builds the description line for a music album,
+// e.g. "Artist · 2004 · 12 tracks"
+func formatAlbumDescription(item jellyfin.MediaItem) string {
+	var parts []string
+	if artist := albumArtist(item); artist != "" {
+		parts = append(parts, artist)
+	}
+	if item.ProductionYear > 0 {
+		parts = append(parts, fmt.Sprintf("%d", item.ProductionYear))
+	}
+	if item.ChildCount == 1 {
+		parts = append(parts, "1 track")
+	} else if item.ChildCount > 1 {
+		parts = append(parts, fmt.Sprintf("%d tracks", item.ChildCount))
 	}
+	if len(parts) == 0 {
+		return "album"
+	}
+	return strings.Join(parts, " · ")
 }
 
-// Command to play media with MPV
-func playMedia(item MediaItem) tea.Cmd {
+// formatDuration converts Jellyfin RunTimeTicks (100ns units) to m:ss or h:mm:ss
+func formatDuration(ticks int64) string {
+	total := ticks / ticksPerSecond
+	hours, minutes, seconds := total/3600, (total%3600)/60, total%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
+// Command to search for media
+func searchMedia(ctx context.Context, client *jellyfin.Client, query string, startIndex int) tea.Cmd {
 	return func() tea.Msg {
-		fmt.Printf("Playing %s (%s) with MPV\n", item.ItemTitle, item.ID)
-		
-		// Actually play the media with MPV
-		cmd := exec.Command("mpv", item.StreamURL)
-		err := cmd.Start()
+		page, err := client.Search(ctx, query, startIndex, searchPageSize)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
 		if err != nil {
-			return errorMsg(fmt.Errorf("failed to start MPV: %v", err))
+			return searchResultsMsg{query: query, startIndex: startIndex, err: err}
 		}
 		
-		return nil
+		// Convert jellyfin.MediaItem to our MediaItem
+		mediaItems := make([]MediaItem, len(page.Items))
+		for i, item := range page.Items {
+			mediaItems[i] = MediaItem{
+				ID:        item.ID,
+				ItemTitle: item.Name,
+				ItemType:  item.Type,
+				IsFolder:  item.IsFolder,
+				UserData:  item.UserData,
+				ImageTag:  item.ImageTags["Primary"],
+				Type:      item.MediaType,
+				// You can construct image URL if needed
+				StreamURL:    streamURL(client, item),
+				DisplayTitle: searchTitle(item),
+				DisplayDesc:  describeItem(item),
+				Resolution:   resolutionBadge(item.Width, item.Height),
+				IndexNumber:  item.IndexNumber,
+				SeriesName:   item.SeriesName,
+				SeasonNumber: item.ParentIndexNumber,
+				Year:         item.ProductionYear,
+				Artist:       trackArtist(item),
+			}
+		}
+		
+		addLibraryNames(ctx, client, mediaItems)
+
+		return searchResultsMsg{query: query, startIndex: startIndex, items: mediaItems, total: page.TotalCount}
 	}
 }
 
 // Add the Init method to implement the tea.Model interface
 func (m Model) Init() tea.Cmd {
-	return nil
+	if interval := m.config.healthCheckInterval(); interval > 0 {
+		return tea.Batch(m.initCmd, m.player.listen(), pingServer(m.client, interval))
+	}
+	return tea.Batch(m.initCmd, m.player.listen())
 }
 
 func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	printPlay := flag.Bool("print-play", false, "show the player command instead of launching the player")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [command]\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "Commands:")
+		fmt.Fprintln(flag.CommandLine.Output(), "  doctor    check the connection to the server step by step")
+		fmt.Fprintln(flag.CommandLine.Output(), "  reset     remove the saved session and search history, and optionally the config")
+		fmt.Fprintln(flag.CommandLine.Output(), "  play ID   play an item in MPV, resuming where it was left off")
+		fmt.Fprintln(flag.CommandLine.Output(), "  open ID   start the app on an item's details")
+		fmt.Fprintln(flag.CommandLine.Output(), "\nFlags:")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var openID string
+	switch flag.Arg(0) {
+	case "":
+	case "doctor":
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(runDoctor(os.Stdout, config))
+	case "reset":
+		os.Exit(runReset(os.Stdout, os.Stdin))
+	case "play", "open":
+		if flag.NArg() != 2 {
+			fmt.Fprintf(os.Stderr, "Usage: %s %s <item ID>\n", os.Args[0], flag.Arg(0))
+			os.Exit(2)
+		}
+		if flag.Arg(0) == "open" {
+			openID = flag.Arg(1)
+			break
+		}
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(runPlay(os.Stdout, config, flag.Arg(1), config.PrintPlay || *printPlay))
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	model := initialModel()
+	model.printPlay = model.config.PrintPlay || *printPlay
+	if openID != "" {
+		var err error
+		if model, err = model.openItem(openID); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	finalModel, err := p.Run()
+
+	// Bubble Tea stops handling signals once it exits; catch them while
+	// playback reports are flushed so a second one only cuts the wait short
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	model.player.shutdown(model.config.StopPlayerOnExit, shutdownTimeout, interrupt)
+
+	// An interrupt (SIGINT when input isn't a terminal) is a normal exit
+	if err != nil && !errors.Is(err, tea.ErrInterrupted) {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
+
+	if m, ok := finalModel.(Model); ok && m.config.RestoreSession {
+		if err := saveSession(m.currentSession()); err != nil {
+			fmt.Printf("Error saving session: %v\n", err)
+		}
+	}
 } 
\ No newline at end of file
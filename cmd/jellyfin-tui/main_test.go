@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testModel starts the app against a server, with its state kept in a
+// temporary home directory
+func testModel(t *testing.T, serverURL string) Model {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := Config{ServerURL: serverURL, APIKey: "key", UserID: "user"}
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(home, ".config", "jellyfin-tui")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return initialModel()
+}
+
+func TestLeavingViewCancelsFetch(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		// Answer only once the client gives up, or the test is over
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	m, fetch := testModel(t, server.URL).openRecent()
+	result := make(chan tea.Msg, 1)
+	go func() { result <- fetch() }()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the recently played view didn't fetch anything")
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if view := updated.(Model).currentView; view != "main" {
+		t.Fatalf("esc went to %q, want main", view)
+	}
+
+	select {
+	case msg := <-result:
+		if msg != nil {
+			t.Errorf("the cancelled fetch returned %#v, want nothing", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("leaving the view didn't cancel its fetch")
+	}
+}
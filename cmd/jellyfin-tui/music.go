@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// Music browsing by genre and album artist: "genres" and "artists" list
+// them, and "musicalbums" lists the albums of the one chosen
+type fetchGenresMsg []MediaItem
+type fetchArtistsMsg []MediaItem
+type fetchMusicAlbumsMsg []list.Item
+
+// openGenres lists the music genres
+func (m Model) openGenres() (Model, tea.Cmd) {
+	m.returnTo["genres"] = m.currentView
+	m.genresList.SetItems([]list.Item{})
+	m.currentView = "genres"
+	ctx := m.viewContext()
+	return m, fetchGenres(ctx, m.client)
+}
+
+// openArtists lists the album artists
+func (m Model) openArtists() (Model, tea.Cmd) {
+	m.returnTo["artists"] = m.currentView
+	m.artistsList.SetItems([]list.Item{})
+	m.currentView = "artists"
+	ctx := m.viewContext()
+	return m, fetchArtists(ctx, m.client)
+}
+
+// openMusicAlbums lists the albums of a genre, grouped by album artist, or
+// of an album artist
+func (m Model) openMusicAlbums(parent MediaItem) (Model, tea.Cmd) {
+	m.returnTo["musicalbums"] = m.currentView
+	m.musicAlbumsList.SetItems([]list.Item{})
+	m.musicAlbumsList.Title = displayText(parent.Title())
+	m.musicAlbumsParent = parent.ID
+	m.currentView = "musicalbums"
+	ctx := m.viewContext()
+	return m, fetchMusicAlbums(ctx, m.client, parent)
+}
+
+// updateMusicIndex handles input in the genres and artists views
+func (m Model) updateMusicIndex(msg tea.Msg) (Model, tea.Cmd) {
+	l := m.listForView(m.currentView)
+
+	var cmd tea.Cmd
+	*l, cmd = l.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" && l.FilterState() != list.Filtering {
+		if selectedItem, ok := l.SelectedItem().(MediaItem); ok && selectedItem.ID != "" {
+			return m.openMusicAlbums(selectedItem)
+		}
+	}
+
+	return m, cmd
+}
+
+// updateMusicAlbums handles input in the albums of a genre or artist
+func (m Model) updateMusicAlbums(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.musicAlbumsList, cmd = m.musicAlbumsList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" && m.musicAlbumsList.FilterState() != list.Filtering {
+		if selectedItem, ok := m.musicAlbumsList.SelectedItem().(MediaItem); ok && selectedItem.ID != "" {
+			return m.drillInto(selectedItem)
+		}
+	}
+
+	return m, cmd
+}
+
+// Command to fetch the music genres
+func fetchGenres(ctx context.Context, client *jellyfin.Client) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetMusicGenres(ctx)
+		if err != nil {
+			return fetchError(err)
+		}
+		return fetchGenresMsg(convertItems(client, items))
+	}
+}
+
+// Command to fetch the album artists
+func fetchArtists(ctx context.Context, client *jellyfin.Client) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetAlbumArtists(ctx)
+		if err != nil {
+			return fetchError(err)
+		}
+		return fetchArtistsMsg(convertItems(client, items))
+	}
+}
+
+// Command to fetch the albums of a genre or album artist
+func fetchMusicAlbums(ctx context.Context, client *jellyfin.Client, parent MediaItem) tea.Cmd {
+	return func() tea.Msg {
+		if parent.ItemType == "MusicArtist" {
+			items, err := client.GetAlbumsByArtist(ctx, parent.ID)
+			if err != nil {
+				return fetchError(err)
+			}
+			return fetchMusicAlbumsMsg(convertToListItems(convertAlbums(client, items)))
+		}
+
+		items, err := client.GetAlbumsByGenre(ctx, parent.ID)
+		if err != nil {
+			return fetchError(err)
+		}
+		return fetchMusicAlbumsMsg(groupByAlbumArtist(client, items))
+	}
+}
+
+// groupByAlbumArtist converts albums sorted by album artist, adding a header
+// before each artist's albums
+func groupByAlbumArtist(client *jellyfin.Client, items []jellyfin.MediaItem) []list.Item {
+	albums := convertAlbums(client, items)
+
+	var listItems []list.Item
+	headerAt := -1
+	for i, item := range items {
+		if i == 0 || albumArtist(item) != albumArtist(items[i-1]) {
+			title := albumArtist(item)
+			if title == "" {
+				title = "Unknown Artist"
+			}
+			listItems = append(listItems, sectionHeader{title: title})
+			headerAt = len(listItems) - 1
+		}
+
+		header := listItems[headerAt].(sectionHeader)
+		header.count++
+		listItems[headerAt] = header
+
+		listItems = append(listItems, albums[i])
+	}
+	return listItems
+}
+
+// trackArtist returns a track's main artist, who it's scrobbled under
+func trackArtist(item jellyfin.MediaItem) string {
+	if len(item.Artists) > 0 {
+		return item.Artists[0]
+	}
+	return albumArtist(item)
+}
+
+// albumArtist returns who an album is filed under, rather than the artists
+// of its tracks
+func albumArtist(item jellyfin.MediaItem) string {
+	if len(item.AlbumArtists) > 0 {
+		names := make([]string, len(item.AlbumArtists))
+		for i, artist := range item.AlbumArtists {
+			names[i] = artist.Name
+		}
+		return strings.Join(names, ", ")
+	}
+	return item.AlbumArtist
+}
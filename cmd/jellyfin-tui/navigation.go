@@ -0,0 +1,347 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// folderLevel is one level of generic library browsing
+type folderLevel struct {
+	parentID string // Empty for the library listing itself
+	list     list.Model
+}
+
+// fetchChildrenMsg carries the children of a folder level
+type fetchChildrenMsg struct {
+	parentID string
+	items    []MediaItem
+}
+
+// drillInto routes a selected item by its type: folders are opened and
+// their children fetched, playable items are played. Series, seasons and
+// albums keep their dedicated views, which format their children.
+func (m Model) drillInto(item MediaItem) (Model, tea.Cmd) {
+	switch item.ItemType {
+	case "Series":
+		m.returnTo["seasons"] = m.currentView
+		m.currentItem = item
+		m.series = item
+		m.currentView = "seasons"
+		ctx := m.viewContext()
+		return m, fetchSeasons(ctx, m.client, item.ID)
+	case "Season":
+		m.returnTo["episodes"] = m.currentView
+		m.currentItem = item
+		m.currentView = "episodes"
+		ctx := m.viewContext()
+		return m, fetchEpisodes(ctx, m.client, item.ID)
+	case "MusicAlbum":
+		m.returnTo["tracks"] = m.currentView
+		m.currentItem = item
+		m.tracksList.Title = displayText(item.ItemTitle)
+		m.currentView = "tracks"
+		ctx := m.viewContext()
+		return m, fetchTracks(ctx, m.client, item.ID)
+	}
+
+	if item.IsFolder {
+		m = m.pushFolder(item.ID, item.Title())
+		ctx := m.viewContext()
+		return m, fetchChildren(ctx, m.client, item.ID)
+	}
+
+	if item.ItemType == "Book" {
+		m.status = fmt.Sprintf("%s is an ebook; ebooks aren't supported, open it in the Jellyfin web client", item.Title())
+		return m, nil
+	}
+
+	if item.StreamURL == "" {
+		m.status = fmt.Sprintf("%s can't be played (%s)", item.Title(), item.ItemType)
+		return m, nil
+	}
+	return m.startPlayback(item)
+}
+
+// openLibraries starts generic browsing at the server's library listing
+func (m Model) openLibraries() (Model, tea.Cmd) {
+	m = m.pushFolder("", "Libraries")
+	ctx := m.viewContext()
+	return m, fetchLibraries(ctx, m.client, m.config.HiddenLibraries)
+}
+
+// pushFolder adds a folder level and shows it, remembering where esc should
+// return to once the last level is closed
+func (m Model) pushFolder(parentID, title string) Model {
+	if m.currentView != "folder" {
+		// Entering browsing from another view starts a new trail
+		m.returnTo["folder"] = m.currentView
+		m.folderStack = nil
+	}
+
+	l := list.New([]list.Item{}, newItemDelegate(m.config), m.listWidth, m.listHeight)
+	l.Title = displayText(title)
+
+	// Copy so earlier models don't share the backing array
+	m.folderStack = append(append([]folderLevel(nil), m.folderStack...), folderLevel{parentID: parentID, list: l})
+	m.currentView = "folder"
+	return m
+}
+
+// popFolder closes the current folder level
+func (m Model) popFolder() Model {
+	if len(m.folderStack) > 0 {
+		m.folderStack = m.folderStack[:len(m.folderStack)-1]
+	}
+	if len(m.folderStack) == 0 {
+		m.currentView = m.returnTo["folder"]
+		if m.currentView == "" {
+			m.currentView = "main"
+		}
+	}
+	return m
+}
+
+// setFolderItems fills the folder level the children were fetched for
+func (m *Model) setFolderItems(msg fetchChildrenMsg) {
+	for i := range m.folderStack {
+		if m.folderStack[i].parentID == msg.parentID {
+			m.folderStack[i].list.SetItems(convertToListItems(msg.items))
+			m.restoreFolderPosition(msg.parentID, &m.folderStack[i].list)
+			return
+		}
+	}
+	// The level was closed before its children arrived
+}
+
+// updateFolder handles input in the generic folder view
+func (m Model) updateFolder(msg tea.Msg) (Model, tea.Cmd) {
+	l := m.listForView("folder")
+	if l == nil {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	*l, cmd = l.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" && l.FilterState() != list.Filtering {
+		if selectedItem, ok := l.SelectedItem().(MediaItem); ok && selectedItem.ID != "" {
+			return m.drillInto(selectedItem)
+		}
+	}
+
+	return m, cmd
+}
+
+// Command to fetch the server's libraries
+func fetchLibraries(ctx context.Context, client *jellyfin.Client, hidden []string) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetLibraries(ctx)
+		if err != nil {
+			return fetchError(err)
+		}
+		return fetchChildrenMsg{parentID: "", items: convertItems(client, withoutHidden(items, hidden))}
+	}
+}
+
+// withoutHidden leaves out the libraries hidden in the config, matched by
+// name, ignoring case, or by ID
+func withoutHidden(libraries []jellyfin.MediaItem, hidden []string) []jellyfin.MediaItem {
+	if len(hidden) == 0 {
+		return libraries
+	}
+	var shown []jellyfin.MediaItem
+	for _, library := range libraries {
+		if !slices.ContainsFunc(hidden, func(h string) bool {
+			return strings.EqualFold(h, library.Name) || h == library.ID
+		}) {
+			shown = append(shown, library)
+		}
+	}
+	return shown
+}
+
+// Command to fetch the children of a folder
+func fetchChildren(ctx context.Context, client *jellyfin.Client, parentID string) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetChildren(ctx, parentID)
+		if err != nil {
+			return fetchError(err)
+		}
+		return fetchChildrenMsg{parentID: parentID, items: convertItems(client, items)}
+	}
+}
+
+// refreshTimeout bounds queueing a metadata refresh; the refresh itself
+// runs on the server afterwards
+const refreshTimeout = 15 * time.Second
+
+// Command to queue a metadata refresh of an item on the server
+func refreshMetadata(client *jellyfin.Client, item MediaItem) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+
+		err := client.RefreshItemMetadata(ctx, item.ID)
+
+		var statusErr *jellyfin.StatusError
+		switch {
+		case errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusForbidden || statusErr.StatusCode == http.StatusUnauthorized):
+			return statusMsg("Refreshing metadata needs an administrator's API key")
+		case err != nil:
+			return statusMsg(fmt.Sprintf("Metadata refresh failed: %v", err))
+		}
+		return statusMsg(fmt.Sprintf("Metadata refresh queued for %s", item.Title()))
+	}
+}
+
+// convertItems converts items of any type for generic browsing, choosing
+// the stream URL from the item's media type
+func convertItems(client *jellyfin.Client, items []jellyfin.MediaItem) []MediaItem {
+	mediaItems := make([]MediaItem, len(items))
+	for i, item := range items {
+		mediaItems[i] = MediaItem{
+			ID:          item.ID,
+			ItemTitle:   item.Name,
+			ItemType:    item.Type,
+			IsFolder:    item.IsFolder,
+			Type:        item.Type,
+			StreamURL:   streamURL(client, item),
+			DisplayDesc: describeItem(item),
+			UserData:    item.UserData,
+			ImageTag:    item.ImageTags["Primary"],
+
+			RunTimeTicks: item.RunTimeTicks,
+			Artist:       trackArtist(item),
+			Album:        item.Album,
+			Resolution:   resolutionBadge(item.Width, item.Height),
+			IndexNumber:  item.IndexNumber,
+			SeriesName:   item.SeriesName,
+			SeasonNumber: item.ParentIndexNumber,
+			Year:         item.ProductionYear,
+		}
+	}
+	return mediaItems
+}
+
+// streamURL returns the URL to play an item, or "" if it isn't playable
+func streamURL(client *jellyfin.Client, item jellyfin.MediaItem) string {
+	if item.IsFolder {
+		return ""
+	}
+	switch item.MediaType {
+	case "Video":
+		return client.GetStreamURL(item.ID)
+	case "Audio":
+		return client.GetAudioStreamURL(item.ID)
+	}
+	return ""
+}
+
+// describeItem builds the description line for item types that need more
+// than their type, or "" to fall back to the type
+func describeItem(item jellyfin.MediaItem) string {
+	switch item.Type {
+	case "AudioBook":
+		parts := []string{"audiobook"}
+		if author := itemAuthor(item); author != "" {
+			parts = append(parts, author)
+		}
+		if item.RunTimeTicks > 0 {
+			parts = append(parts, formatDuration(item.RunTimeTicks))
+		}
+		return strings.Join(parts, " · ")
+	case "Book":
+		parts := []string{"ebook"}
+		if author := itemAuthor(item); author != "" {
+			parts = append(parts, author)
+		}
+		return strings.Join(append(parts, "not supported"), " · ")
+	}
+	return ""
+}
+
+// itemAuthor returns the author of a book, which Jellyfin stores as its artist
+func itemAuthor(item jellyfin.MediaItem) string {
+	if item.AlbumArtist != "" {
+		return item.AlbumArtist
+	}
+	if len(item.Artists) > 0 {
+		return strings.Join(item.Artists, ", ")
+	}
+	return ""
+}
+
+// episodeTitle formats an episode with its series, e.g. "Show · S01E02 · Pilot"
+func episodeTitle(item jellyfin.MediaItem) string {
+	title := item.Name
+	if item.IndexNumber > 0 {
+		title = fmt.Sprintf("S%02dE%02d · %s", item.ParentIndexNumber, item.IndexNumber, item.Name)
+	}
+	if item.SeriesName != "" {
+		title = item.SeriesName + " · " + title
+	}
+	return title
+}
+
+// searchTitle tells apart results that share a name: episodes show their
+// series and number, movies and series their year
+func searchTitle(item jellyfin.MediaItem) string {
+	switch item.Type {
+	case "Episode":
+		return episodeTitle(item)
+	case "Movie", "Series":
+		if item.ProductionYear > 0 {
+			return fmt.Sprintf("%s (%d)", item.Name, item.ProductionYear)
+		}
+	case "Season":
+		if item.SeriesName != "" {
+			return item.SeriesName + " · " + item.Name
+		}
+	}
+	return item.Name
+}
+
+// addLibraryNames tells apart movies that share a title and year, such as
+// the same film in a "Movies" and a "4K Movies" library, by adding the
+// library each is in to its description. Only those movies are looked up,
+// all at once within the client's concurrency limit, and one that can't be
+// is left as it was.
+func addLibraryNames(ctx context.Context, client *jellyfin.Client, items []MediaItem) {
+	titles := map[string]int{}
+	for _, item := range items {
+		if item.ItemType == "Movie" {
+			titles[item.DisplayTitle]++
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		if item.ItemType != "Movie" || titles[item.DisplayTitle] < 2 {
+			continue
+		}
+		wg.Add(1)
+		go func(item *MediaItem) {
+			defer wg.Done()
+			library, err := client.GetLibraryName(ctx, item.ID)
+			if err != nil || library == "" {
+				return
+			}
+			if item.DisplayDesc == "" {
+				item.DisplayDesc = library
+			} else {
+				item.DisplayDesc += " · " + library
+			}
+		}(&items[i])
+	}
+	wg.Wait()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+func TestAddLibraryNames(t *testing.T) {
+	libraries := map[string]string{"hd": "Movies", "uhd": "4K Movies", "other": "Movies"}
+	var mu sync.Mutex
+	lookedUp := map[string]bool{}
+	// Lookups are answered once both are in, so they have to run together
+	inFlight, both := 0, make(chan struct{})
+	var together atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/Items/"), "/Ancestors")
+		mu.Lock()
+		lookedUp[id] = true
+		if inFlight++; inFlight == 2 && !together.Load() {
+			together.Store(true)
+			close(both)
+		}
+		mu.Unlock()
+		select {
+		case <-both:
+		case <-time.After(time.Second):
+		}
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `[{"Name": "Blade Runner", "Type": "Folder"}, {"Name": %q, "Type": "CollectionFolder"}, {"Name": "Root", "Type": "AggregateFolder"}]`, libraries[id])
+	}))
+	defer server.Close()
+	client := jellyfin.NewClient(server.URL, "key")
+	client.UserID = "user"
+
+	items := []MediaItem{
+		{ID: "hd", ItemType: "Movie", DisplayTitle: "Blade Runner (1982)"},
+		{ID: "uhd", ItemType: "Movie", DisplayTitle: "Blade Runner (1982)"},
+		{ID: "other", ItemType: "Movie", DisplayTitle: "Blade Runner 2049 (2017)"},
+		{ID: "series", ItemType: "Series", DisplayTitle: "Blade Runner (1982)"},
+	}
+	addLibraryNames(context.Background(), client, items)
+
+	for i, want := range []string{"Movies", "4K Movies", "", ""} {
+		if items[i].DisplayDesc != want {
+			t.Errorf("%s: description %q, want %q", items[i].ID, items[i].DisplayDesc, want)
+		}
+	}
+	if !together.Load() {
+		t.Error("looked up the libraries one at a time, want them all at once")
+	}
+	if lookedUp["other"] || lookedUp["series"] {
+		t.Errorf("looked up the library of titles no other movie shares: %v", lookedUp)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Some videos are split across several files, such as a movie in "Part 1"
+// and "Part 2". They're played in order as one mpv playlist, and progress is
+// reported against the whole video: a position in a later part counts the
+// length of the parts before it.
+
+// partStarts returns where each file of a video starts within it, the item's
+// own file first, and the length of the whole video. A video in one file
+// has a single start at 0.
+func partStarts(item MediaItem) ([]time.Duration, time.Duration) {
+	starts := []time.Duration{0}
+	end := time.Duration(item.RunTimeTicks) * 100
+	for _, part := range item.Parts {
+		starts = append(starts, end)
+		end += time.Duration(part.RunTimeTicks) * 100
+	}
+	return starts, end
+}
+
+// partsPlaylist returns the player arguments that play every file of a
+// video in order, starting in the part a resume position falls in
+func (m Model) partsPlaylist(item MediaItem, position time.Duration) []string {
+	starts, _ := partStarts(item)
+	first := 0
+	for i := 1; i < len(starts); i++ {
+		// A part of unknown length hides where the later ones start
+		if starts[i] == starts[i-1] || position < starts[i] {
+			break
+		}
+		first = i
+	}
+
+	var args []string
+	if first > 0 {
+		args = append(args, fmt.Sprintf("--playlist-start=%d", first))
+	}
+	for i := range starts {
+		file := item
+		if i > 0 {
+			file.ID, file.SourceID = item.Parts[i-1].ID, ""
+		}
+		url := m.playURL(file)
+		if i == first && position > starts[i] {
+			// Options between --{ and --} apply to that file alone
+			offset := int64((position - starts[i]).Seconds())
+			args = append(args, "--{", fmt.Sprintf("--start=%d", offset), url, "--}")
+			continue
+		}
+		args = append(args, url)
+	}
+	return args
+}
+
+// partOffset returns where the playing file starts within the item, or false
+// if mpv can't say which of its files is playing
+func (pb *playback) partOffset() (time.Duration, bool) {
+	if len(pb.partStarts) <= 1 {
+		return 0, true
+	}
+	index, err := pb.conn.PlaylistPos()
+	if err != nil || index < 0 || index >= len(pb.partStarts) {
+		return 0, false
+	}
+	return pb.partStarts[index], true
+}
@@ -0,0 +1,661 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+	"github.com/fabean/jellyfin-tui/lastfm"
+	"github.com/fabean/jellyfin-tui/mpv"
+)
+
+const (
+	reportTimeout       = 5 * time.Second  // Limit for each playback report
+	shutdownTimeout     = 3 * time.Second  // How long exiting waits for the final reports
+	segmentInterval     = time.Second      // How often the position is checked against skippable segments
+	skipTextDuration    = 5 * time.Second  // How long the skip prompt shows on mpv's OSD
+	quietReportInterval = time.Minute      // How often unchanged progress is still reported, so the server keeps the session
+	fallbackWindow      = 10 * time.Second // mpv failing this soon after starting a direct stream retries it transcoded
+
+	defaultIdleTimeout      = 5 * time.Minute
+	defaultProgressInterval = 10 * time.Second
+)
+
+// player launches mpv and reports what it plays to the server. It's shared
+// by every copy of the model so playback can be wound down on exit.
+type player struct {
+	scrobbler        *lastfm.Client // Nil unless scrobbling is enabled and configured
+	idleTimeout      time.Duration  // Stop polling after this long without progress; 0 never stops
+	progressInterval time.Duration  // How often the position of a playing item is reported
+	fallback         bool           // Retry videos mpv fails to play directly as a transcoded stream
+
+	mu          sync.Mutex
+	trackers    sync.WaitGroup
+	quit        chan struct{} // Closed on shutdown
+	events      chan tea.Msg  // Messages from playback for the model, see listen
+	closed      bool
+	stopPlayers bool      // Terminate mpv on shutdown rather than leave it running
+	active      *playback // The playback started last, while it runs
+}
+
+// playback is one running mpv and the item it plays
+type playback struct {
+	item    MediaItem
+	cmd     *exec.Cmd
+	conn    *mpv.Conn // Nil until mpv's IPC socket is up, or if IPC is unsupported
+	socket  string
+	headers string // mpv config file holding the extra headers, if any
+	exited  chan struct{}
+	exitErr error                   // How mpv exited, set before exited is closed
+	report  jellyfin.PlaybackReport // Last known state
+
+	reported   jellyfin.PlaybackReport // Last progress sent to the server
+	reportedAt time.Time
+
+	now       func() time.Time           // The clock, replaced in tests
+	newTicker func(time.Duration) ticker // Ticks for polling, replaced in tests
+	started   time.Time
+	duration  time.Duration // From the server, or mpv once it has loaded the file
+	scrobbled bool
+	lastMoved time.Time // When the position last changed
+
+	partStarts []time.Duration // Where each file of a video split across several starts, see partStarts
+
+	segments []jellyfin.Segment // Intro and credits, if the server knows them
+	current  *jellyfin.Segment  // Segment the skip prompt is shown for
+	skip     chan jellyfin.Segment
+
+	subtitles chan subtitleAction
+}
+
+// subtitleAction changes the subtitles of a playback
+type subtitleAction int
+
+const (
+	cycleSubtitles      subtitleAction = iota // Switch to the next track, or off after the last
+	toggleSubtitleShown                       // Show or hide the current track
+)
+
+// subtitleStatusMsg reports the subtitles shown after changing them
+type subtitleStatusMsg string
+
+// transcodeFallbackMsg reports that mpv couldn't play a video directly, so
+// it's being played again as a transcoded stream
+type transcodeFallbackMsg struct {
+	item MediaItem
+	err  error
+}
+
+// skipPromptMsg offers to skip the segment playing, or withdraws the offer
+// when skip is nil
+type skipPromptMsg struct {
+	prompt string
+	skip   func()
+}
+
+func newPlayer(config Config) *player {
+	return &player{
+		scrobbler:        config.scrobbler(),
+		idleTimeout:      config.idleTimeout(),
+		progressInterval: config.progressInterval(),
+		fallback:         !config.DisableTranscodeFallback,
+		quit:             make(chan struct{}),
+		events:           make(chan tea.Msg, 16),
+	}
+}
+
+// idleTimeout returns how long playback may go without progress before
+// polling stops, or 0 if it never does
+func (c Config) idleTimeout() time.Duration {
+	switch {
+	case c.IdleTimeout < 0:
+		return 0
+	case c.IdleTimeout == 0:
+		return defaultIdleTimeout
+	}
+	return time.Duration(c.IdleTimeout) * time.Second
+}
+
+// progressInterval returns how often the position of a playing item is
+// reported
+func (c Config) progressInterval() time.Duration {
+	if c.ProgressReportInterval <= 0 {
+		return defaultProgressInterval
+	}
+	return time.Duration(c.ProgressReportInterval) * time.Second
+}
+
+// scrobbler returns the Last.fm client, or nil if scrobbling is off or
+// isn't fully configured
+func (c Config) scrobbler() *lastfm.Client {
+	if !c.Scrobble || c.LastFMAPIKey == "" || c.LastFMAPISecret == "" || c.LastFMSessionKey == "" {
+		return nil
+	}
+	return lastfm.NewClient(c.LastFMAPIKey, c.LastFMAPISecret, c.LastFMSessionKey)
+}
+
+// play starts mpv with argv from playerCommand and tracks its playback in
+// the background
+func (p *player) play(client *jellyfin.Client, item MediaItem, args []string) tea.Cmd {
+	return func() tea.Msg {
+		socket, err := mpv.SocketPath("jellyfin-tui")
+		if err == nil {
+			args = append([]string{args[0], "--input-ipc-server=" + socket}, args[1:]...)
+		}
+		var headers string
+		if len(client.Headers) > 0 {
+			if headers, err = headerFile(client.Headers); err != nil {
+				return errorMsg(err)
+			}
+			args = withInclude(args, headers)
+		}
+
+		// Once the app starts exiting nothing new is started. A start that's
+		// already under way counts as a tracker, so shutdown waits for it and
+		// then stops what it started.
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			if headers != "" {
+				os.Remove(headers)
+			}
+			return nil
+		}
+		p.trackers.Add(1)
+		p.mu.Unlock()
+
+		cmd := exec.Command(args[0], args[1:]...)
+		if err := cmd.Start(); err != nil {
+			p.trackers.Done()
+			if headers != "" {
+				os.Remove(headers)
+			}
+			return errorMsg(fmt.Errorf("failed to start MPV: %v", err))
+		}
+
+		starts, duration := partStarts(item)
+		pb := &playback{
+			item:       item,
+			cmd:        cmd,
+			socket:     socket,
+			headers:    headers,
+			exited:     make(chan struct{}),
+			skip:       make(chan jellyfin.Segment, 1),
+			subtitles:  make(chan subtitleAction, 1),
+			now:        time.Now,
+			newTicker:  newTimeTicker,
+			started:    time.Now(),
+			lastMoved:  time.Now(),
+			duration:   duration,
+			partStarts: starts,
+			report: jellyfin.PlaybackReport{
+				ItemID:        item.ID,
+				MediaSourceID: item.SourceID,
+				PositionTicks: item.UserData.PlaybackPositionTicks,
+				PlayMethod:    playMethod(item),
+			},
+		}
+		go func() {
+			pb.exitErr = cmd.Wait()
+			pb.removeHeaders()
+			close(pb.exited)
+		}()
+
+		p.mu.Lock()
+		p.active = pb
+		p.mu.Unlock()
+		go p.track(client, pb)
+
+		return nil
+	}
+}
+
+// ticker delivers ticks at an interval, like a time.Ticker
+type ticker interface {
+	Chan() <-chan time.Time
+	Reset(d time.Duration)
+	Stop()
+}
+
+// timeTicker is a ticker on the system clock
+type timeTicker struct{ *time.Ticker }
+
+func newTimeTicker(d time.Duration) ticker { return timeTicker{time.NewTicker(d)} }
+
+func (t timeTicker) Chan() <-chan time.Time { return t.C }
+
+// playMethod tells the server how an item is played
+func playMethod(item MediaItem) string {
+	if item.Limited {
+		return "Transcode"
+	}
+	return "DirectStream"
+}
+
+// track reports a playback's start, its position every progressInterval,
+// and where it stopped
+func (p *player) track(client *jellyfin.Client, pb *playback) {
+	defer p.trackers.Done()
+	defer pb.close()
+	defer p.deactivate(pb)
+
+	sendReport(client.ReportPlaybackStart, pb.report)
+	defer p.promptSkip(pb, nil)
+
+	// Servers without segments, or the plugin that finds intros, just don't
+	// offer skipping
+	segmentTicker := pb.newTicker(segmentInterval)
+	defer segmentTicker.Stop()
+	if isVideo(pb.item) && pb.socket != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		pb.segments, _ = client.GetSegments(ctx, pb.item.ID)
+		cancel()
+	}
+	if len(pb.segments) == 0 {
+		segmentTicker.Stop()
+	}
+
+	ticker := pb.newTicker(p.progressInterval)
+	defer ticker.Stop()
+
+	// Paused or stalled for a while: stop polling and reporting until the
+	// position moves again. The wait holds the IPC connection, so it's
+	// stopped before anything else uses it.
+	var idle *idleWait
+	defer func() { idle.stop() }()
+	wake := func() {
+		idle.stop()
+		idle = nil
+		pb.lastMoved = pb.now()
+		ticker.Reset(p.progressInterval)
+	}
+
+	for {
+		select {
+		case <-segmentTicker.Chan():
+			if idle != nil {
+				continue // The position isn't moving
+			}
+			pb.poll()
+			p.checkSegments(pb)
+		case action := <-pb.subtitles:
+			paused := idle != nil
+			idle.stop()
+			pb.poll()
+			p.send(subtitleStatusMsg(pb.changeSubtitles(action)))
+			if paused {
+				// Changing subtitles doesn't move the position
+				idle = waitForActivity(pb)
+			}
+		case segment := <-pb.skip:
+			if idle != nil {
+				wake()
+			}
+			if pb.conn != nil && pb.conn.Seek(segment.End.Seconds()) == nil {
+				pb.report.PositionTicks = int64(segment.End / 100)
+				p.checkSegments(pb)
+			}
+		case <-ticker.Chan():
+			pb.poll()
+			pb.reportProgress(client)
+			p.scrobble(pb)
+
+			if p.idleTimeout > 0 && pb.conn != nil && pb.now().Sub(pb.lastMoved) >= p.idleTimeout {
+				ticker.Stop()
+				idle = waitForActivity(pb)
+			}
+		case <-idle.moved():
+			wake()
+		case <-pb.exited:
+			sendReport(client.ReportPlaybackStopped, pb.report)
+			p.scrobble(pb)
+			if p.failedDirectPlay(pb) {
+				p.send(transcodeFallbackMsg{item: pb.item, err: pb.exitErr})
+			}
+			return
+		case <-p.quit:
+			idle.stop()
+			pb.poll()
+			if p.stopPlayers {
+				pb.cmd.Process.Kill()
+			}
+			sendReport(client.ReportPlaybackStopped, pb.report)
+			return
+		}
+	}
+}
+
+// failedDirectPlay reports whether mpv gave up on a directly streamed video
+// soon after starting: it exits with 1 when it can't initialise playback, 2
+// when it can't play the file and 3 when only some of it played, as with an
+// unsupported codec. Quitting, even straight away, exits with 0 or 4.
+func (p *player) failedDirectPlay(pb *playback) bool {
+	if !p.fallback || pb.item.Limited || !isVideo(pb.item) || pb.now().Sub(pb.started) > fallbackWindow {
+		return false
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(pb.exitErr, &exitErr) {
+		return false
+	}
+	code := exitErr.ExitCode()
+	return code >= 1 && code <= 3
+}
+
+// deactivate forgets a playback that has ended, unless a later one has
+// started
+func (p *player) deactivate(pb *playback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active == pb {
+		p.active = nil
+	}
+}
+
+// changeSubtitles asks the playback started last to change its subtitles;
+// the result is reported with a subtitleStatusMsg
+func (p *player) changeSubtitles(action subtitleAction) tea.Cmd {
+	return func() tea.Msg {
+		p.mu.Lock()
+		pb := p.active
+		p.mu.Unlock()
+		if pb == nil {
+			return statusMsg("Nothing is playing in MPV")
+		}
+		select {
+		case pb.subtitles <- action:
+		default:
+		}
+		return nil
+	}
+}
+
+// changeSubtitles changes the subtitles over IPC and describes the result
+func (pb *playback) changeSubtitles(action subtitleAction) string {
+	if pb.conn == nil {
+		return "Subtitles can't be changed: MPV isn't reachable over IPC"
+	}
+	tracks, err := pb.conn.Tracks()
+	if err != nil {
+		return fmt.Sprintf("Couldn't read MPV's subtitles: %v", err)
+	}
+	if !slices.ContainsFunc(tracks, func(t mpv.Track) bool { return t.Type == "sub" }) {
+		return fmt.Sprintf("%s has no subtitles", pb.item.Title())
+	}
+
+	property := "sub"
+	if action == toggleSubtitleShown {
+		property = "sub-visibility"
+	}
+	if err := pb.conn.Cycle(property); err != nil {
+		return fmt.Sprintf("Couldn't change subtitles: %v", err)
+	}
+
+	var visible bool
+	if err := pb.conn.Get("sub-visibility", &visible); err == nil && !visible {
+		return "Subtitles hidden"
+	}
+	if tracks, err = pb.conn.Tracks(); err != nil {
+		return "Subtitles changed"
+	}
+	for _, track := range tracks {
+		if track.Type == "sub" && track.Selected {
+			return "Subtitles: " + subtitleTrackName(track)
+		}
+	}
+	return "Subtitles off"
+}
+
+// subtitleTrackName names a track by its title and language, e.g.
+// "English SDH (eng)", or by its number if it has neither
+func subtitleTrackName(track mpv.Track) string {
+	switch {
+	case track.Title != "" && track.Lang != "":
+		return fmt.Sprintf("%s (%s)", track.Title, track.Lang)
+	case track.Title != "":
+		return track.Title
+	case track.Lang != "":
+		return track.Lang
+	}
+	return fmt.Sprintf("track %d", track.ID)
+}
+
+// poll updates the playback's report from mpv, keeping the last known
+// state if mpv can't be reached
+func (pb *playback) poll() {
+	if pb.conn == nil {
+		if pb.socket == "" {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		conn, err := mpv.Dial(ctx, pb.socket)
+		cancel()
+		if err != nil {
+			return
+		}
+		pb.conn = conn
+	}
+
+	// Positions in a later part of a video count the parts before it
+	offset, ok := pb.partOffset()
+	if position, err := pb.conn.Position(); err == nil && ok {
+		ticks := int64(position*ticksPerSecond) + int64(offset/100)
+		if ticks != pb.report.PositionTicks {
+			pb.lastMoved = pb.now()
+		}
+		pb.report.PositionTicks = ticks
+	}
+	if paused, err := pb.conn.Paused(); err == nil {
+		pb.report.IsPaused = paused
+	}
+	if pb.duration == 0 {
+		if duration, err := pb.conn.Duration(); err == nil {
+			pb.duration = time.Duration(duration * float64(time.Second))
+		}
+	}
+}
+
+// reportProgress reports the position, unless it would only repeat the last
+// report, as while paused. Unchanged progress is still reported every
+// quietReportInterval so the server doesn't drop the session.
+func (pb *playback) reportProgress(client *jellyfin.Client) {
+	if pb.report == pb.reported && pb.now().Sub(pb.reportedAt) < quietReportInterval {
+		return
+	}
+	sendReport(client.ReportPlaybackProgress, pb.report)
+	pb.reported, pb.reportedAt = pb.report, pb.now()
+}
+
+// checkSegments offers to skip the intro or credits while they play
+func (p *player) checkSegments(pb *playback) {
+	position := time.Duration(pb.report.PositionTicks) * 100
+	var playing *jellyfin.Segment
+	for i, segment := range pb.segments {
+		// Not in its last second, where skipping would gain nothing
+		if position >= segment.Start && position < segment.End-time.Second {
+			playing = &pb.segments[i]
+			break
+		}
+	}
+	if playing != pb.current {
+		p.promptSkip(pb, playing)
+	}
+}
+
+// promptSkip shows the skip prompt for a segment, in the app and on mpv's
+// OSD, or withdraws it if segment is nil
+func (p *player) promptSkip(pb *playback, segment *jellyfin.Segment) {
+	if segment == pb.current {
+		return
+	}
+	pb.current = segment
+	if segment == nil {
+		p.send(skipPromptMsg{})
+		return
+	}
+
+	name := "intro"
+	if segment.Type == "Outro" {
+		name = "credits"
+	}
+	skipped := *segment
+	p.send(skipPromptMsg{
+		prompt: fmt.Sprintf("Press i to skip the %s of %s", name, pb.item.Title()),
+		skip: func() {
+			select {
+			case pb.skip <- skipped:
+			default:
+			}
+		},
+	})
+	if pb.conn != nil {
+		pb.conn.ShowText(fmt.Sprintf("Skip %s: press i in jellyfin-tui", name), skipTextDuration)
+	}
+}
+
+// send passes a message to the model, dropping it if the model has fallen
+// behind
+func (p *player) send(msg tea.Msg) {
+	select {
+	case p.events <- msg:
+	default:
+	}
+}
+
+// listen waits for the next message from playback; the model listens again
+// after each one
+func (p *player) listen() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-p.events:
+			return msg
+		case <-p.quit:
+			return nil
+		}
+	}
+}
+
+// idleWait waits in the background for the position of an idle playback
+// to move
+type idleWait struct {
+	cancel context.CancelFunc
+	done   chan struct{} // Closed once the wait is over
+}
+
+// waitForActivity starts waiting for the position to change, which mpv
+// notifies, so nothing is polled meanwhile
+func waitForActivity(pb *playback) *idleWait {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &idleWait{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(w.done)
+		pb.conn.WaitForChange(ctx, "time-pos")
+	}()
+	return w
+}
+
+// moved returns a channel closed once the position has moved or the wait
+// has failed; nil, which never receives, if nothing is being waited for
+func (w *idleWait) moved() <-chan struct{} {
+	if w == nil {
+		return nil
+	}
+	return w.done
+}
+
+// stop ends the wait, returning once the IPC connection is free again
+func (w *idleWait) stop() {
+	if w == nil {
+		return
+	}
+	w.cancel()
+	<-w.done
+}
+
+// scrobble scrobbles a track to Last.fm once it's been played past the
+// scrobble point. Failures are ignored, like playback reports.
+func (p *player) scrobble(pb *playback) {
+	if p.scrobbler == nil || pb.scrobbled || pb.item.ItemType != "Audio" {
+		return
+	}
+	point := lastfm.ScrobblePoint(pb.duration)
+	if point == 0 || time.Duration(pb.report.PositionTicks)*100 < point {
+		return
+	}
+
+	pb.scrobbled = true
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+	p.scrobbler.Scrobble(ctx, lastfm.Scrobble{
+		Artist:    pb.item.Artist,
+		Track:     pb.item.ItemTitle,
+		Album:     pb.item.Album,
+		Timestamp: pb.started,
+		Duration:  pb.duration,
+	})
+}
+
+// close releases the IPC connection and removes the socket once mpv is gone
+func (pb *playback) close() {
+	if pb.conn != nil {
+		pb.conn.Close()
+	}
+	// mpv read the headers when it started, so even one left running no
+	// longer needs them
+	pb.removeHeaders()
+	select {
+	case <-pb.exited:
+		if pb.socket != "" {
+			os.Remove(pb.socket)
+		}
+	default:
+		// mpv was left running and still owns the socket
+	}
+}
+
+// removeHeaders removes the file of extra headers passed to mpv
+func (pb *playback) removeHeaders() {
+	if pb.headers != "" {
+		os.Remove(pb.headers)
+	}
+}
+
+// sendReport sends a playback report. Failures are ignored: a report that
+// doesn't reach the server mustn't interrupt playback.
+func sendReport(send func(context.Context, jellyfin.PlaybackReport) error, report jellyfin.PlaybackReport) {
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+	send(ctx, report)
+}
+
+// shutdown stops tracking playback, terminating the players if stopPlayers
+// is set, and waits for the final reports until timeout or an interrupt
+func (p *player) shutdown(stopPlayers bool, timeout time.Duration, interrupt <-chan os.Signal) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	p.stopPlayers = stopPlayers
+	close(p.quit)
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.trackers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	case <-interrupt:
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+func TestReportProgressInterval(t *testing.T) {
+	var reports atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/Sessions/Playing/Progress" {
+			reports.Add(1)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := jellyfin.NewClient(server.URL, "key")
+
+	clock := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	pb := &playback{
+		now:    func() time.Time { return clock },
+		report: jellyfin.PlaybackReport{ItemID: "item", PositionTicks: 1},
+	}
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		moved   bool
+		want    int32
+	}{
+		{"first report", 0, false, 1},
+		{"unchanged", 10 * time.Second, false, 1},
+		{"moved", 10 * time.Second, true, 2},
+		{"paused", 10 * time.Second, false, 2},
+		{"paused almost a minute", quietReportInterval - 11*time.Second, false, 2},
+		{"paused a minute", time.Second, false, 3},
+		{"still paused", time.Second, false, 3},
+	}
+	for _, step := range steps {
+		clock = clock.Add(step.advance)
+		if step.moved {
+			pb.report.PositionTicks += int64(10 * time.Second / 100)
+		}
+		pb.reportProgress(client)
+		if got := reports.Load(); got != step.want {
+			t.Fatalf("%s: %d reports sent, want %d", step.name, got, step.want)
+		}
+	}
+}
+
+func TestProgressIntervalConfig(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    time.Duration
+	}{
+		{0, defaultProgressInterval},
+		{-5, defaultProgressInterval},
+		{3, 3 * time.Second},
+		{30, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := (Config{ProgressReportInterval: tt.seconds}).progressInterval(); got != tt.want {
+			t.Errorf("progressInterval with progress_report_interval %d = %v, want %v", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+// fakeTicker ticks only when the test sends on it. Track asks for its channel
+// each time round its loop, so ready tells the test the last tick is done.
+type fakeTicker struct {
+	interval time.Duration
+	c        chan time.Time
+	ready    chan struct{}
+}
+
+func (t *fakeTicker) Chan() <-chan time.Time {
+	select {
+	case t.ready <- struct{}{}:
+	default:
+	}
+	return t.c
+}
+
+func (t *fakeTicker) Reset(d time.Duration) { t.interval = d }
+func (t *fakeTicker) Stop()                 {}
+
+// fakeMPV answers IPC commands on a Unix socket like an mpv that plays for
+// as many reads of its position as moves, a second each, then pauses
+func fakeMPV(t *testing.T, moves int) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "mpv.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var position float64
+		paused := false
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var request struct {
+				Command   []interface{} `json:"command"`
+				RequestID int           `json:"request_id"`
+			}
+			if json.Unmarshal(scanner.Bytes(), &request) != nil || len(request.Command) < 2 {
+				continue
+			}
+			var data interface{}
+			switch request.Command[1] {
+			case "time-pos":
+				if moves > 0 {
+					moves--
+					position++
+				} else {
+					paused = true
+				}
+				data = position
+			case "pause":
+				data = paused
+			case "duration":
+				data = 3600.0
+			}
+			reply, _ := json.Marshal(map[string]interface{}{"request_id": request.RequestID, "error": "success", "data": data})
+			conn.Write(append(reply, '\n'))
+		}
+	}()
+	return socket
+}
+
+func TestTrackReportsAtConfiguredInterval(t *testing.T) {
+	var reports atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/Sessions/Playing/Progress" {
+			reports.Add(1)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := jellyfin.NewClient(server.URL, "key")
+
+	p := newPlayer(Config{ProgressReportInterval: 3})
+
+	// The clock moves on by the interval with each tick
+	var clock atomic.Int64
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	tickers := make(chan *fakeTicker, 2)
+	pb := &playback{
+		item:      MediaItem{ID: "track", ItemType: "Audio"},
+		socket:    fakeMPV(t, 5),
+		exited:    make(chan struct{}),
+		skip:      make(chan jellyfin.Segment, 1),
+		subtitles: make(chan subtitleAction, 1),
+		now:       func() time.Time { return start.Add(time.Duration(clock.Load())) },
+		newTicker: func(d time.Duration) ticker {
+			t := &fakeTicker{interval: d, c: make(chan time.Time), ready: make(chan struct{}, 1)}
+			tickers <- t
+			return t
+		},
+		started:   start,
+		lastMoved: start,
+	}
+	p.trackers.Add(1)
+	go p.track(client, pb)
+
+	<-tickers // Segments
+	progress := <-tickers
+	if progress.interval != 3*time.Second {
+		t.Fatalf("progress ticks every %v, want the configured 3s", progress.interval)
+	}
+
+	tick := func(n int) {
+		for i := 0; i < n; i++ {
+			<-progress.ready
+			now := time.Duration(clock.Add(int64(progress.interval)))
+			progress.c <- start.Add(now)
+		}
+	}
+	tick(5) // Playing: every tick is reported
+	tick(1) // Paused: reported once as paused...
+	tick(int(quietReportInterval/progress.interval) - 1)
+	tick(1) // ...then again after quietReportInterval
+
+	close(pb.exited)
+	p.trackers.Wait()
+	if got, want := reports.Load(), int32(5+1+1); got != want {
+		t.Errorf("%d progress reports sent, want %d", got, want)
+	}
+}
+
+func TestNoPlaybackAfterShutdown(t *testing.T) {
+	p := newPlayer(Config{})
+	p.shutdown(true, time.Second, nil)
+
+	// A player that can't start shows whether a start was attempted
+	client := jellyfin.NewClient("http://jellyfin", "key")
+	play := p.play(client, MediaItem{ID: "movie", ItemType: "Movie"}, []string{filepath.Join(t.TempDir(), "mpv")})
+	if msg := play(); msg != nil {
+		t.Errorf("playing after shutdown returned %#v, want nothing started", msg)
+	}
+}
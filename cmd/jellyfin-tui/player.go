@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// playerCommand returns the full argv used to play an item, resuming from
+// the saved position if it was partially watched. A video split across
+// files plays them all, see partsPlaylist.
+func (m Model) playerCommand(item MediaItem) []string {
+	title := mediaTitle(item)
+	status := strings.ReplaceAll(title, "$", "$$")
+	if len(item.Parts) > 0 {
+		status += " (part ${playlist-pos-1} of ${playlist-count})"
+	}
+	args := []string{
+		"mpv",
+		// Shown instead of the stream URL. The status line expands
+		// properties, so a $ in the title is doubled to print as-is.
+		"--force-media-title=" + title,
+		"--term-status-msg=" + status +
+			"  ${?pause==yes:(Paused) }${time-pos} / ${duration} (${percent-pos}%)",
+	}
+	if isVideo(item) && (item.Limited || m.config.streamingProtocol() == jellyfin.ProtocolHLS) {
+		// Play the best of the variants the server offers
+		args = append(args, "--hls-bitrate=max")
+	}
+	ticks := item.UserData.PlaybackPositionTicks
+	if len(item.Parts) > 0 {
+		return append(args, m.partsPlaylist(item, time.Duration(ticks)*100)...)
+	}
+	if ticks > 0 {
+		args = append(args, fmt.Sprintf("--start=%d", ticks/ticksPerSecond))
+	}
+	return append(args, m.playURL(item))
+}
+
+// mediaTitle names an item for the player: "Show - S01E02 - Episode",
+// "Movie (1999)", "Artist - Track", or just its name
+func mediaTitle(item MediaItem) string {
+	switch {
+	case item.ItemType == "Episode" && item.SeriesName != "":
+		return fmt.Sprintf("%s - S%02dE%02d - %s", item.SeriesName, item.SeasonNumber, item.IndexNumber, item.ItemTitle)
+	case item.ItemType == "Movie" && item.Year > 0:
+		return fmt.Sprintf("%s (%d)", item.ItemTitle, item.Year)
+	case item.ItemType == "Audio" && item.Artist != "":
+		return item.Artist + " - " + item.ItemTitle
+	}
+	return item.ItemTitle
+}
+
+// playURL returns the URL the player streams an item from: for videos, a
+// stream over the configured protocol, or transcoded within the bandwidth
+// limits; otherwise the item's own
+func (m Model) playURL(item MediaItem) string {
+	if !isVideo(item) {
+		return item.StreamURL
+	}
+	return m.client.GetPlayURL(item.ID, jellyfin.PlayOptions{
+		SourceID:  item.SourceID,
+		Protocol:  m.config.streamingProtocol(),
+		Container: m.config.StreamContainer,
+		Transcode: item.Limited,
+		Limits:    m.config.streamLimits(),
+	})
+}
+
+// streamingProtocol returns how videos are streamed: jellyfin.ProtocolDirect
+// unless HLS is configured
+func (c Config) streamingProtocol() string {
+	if strings.EqualFold(c.StreamingProtocol, jellyfin.ProtocolHLS) {
+		return jellyfin.ProtocolHLS
+	}
+	return jellyfin.ProtocolDirect
+}
+
+// streamLimits returns the configured bandwidth limits
+func (c Config) streamLimits() jellyfin.StreamLimits {
+	return jellyfin.StreamLimits{MaxBitrate: c.MaxStreamingBitrate, MaxHeight: c.MaxStreamingHeight}
+}
+
+// describeStreamLimits describes the bandwidth limits, e.g. "4 Mbps, 720p",
+// or returns "" if there are none
+func (c Config) describeStreamLimits() string {
+	var limits []string
+	if c.MaxStreamingBitrate > 0 {
+		limits = append(limits, strconv.FormatFloat(float64(c.MaxStreamingBitrate)/1000000, 'f', -1, 64)+" Mbps")
+	}
+	if c.MaxStreamingHeight > 0 {
+		limits = append(limits, fmt.Sprintf("%dp", c.MaxStreamingHeight))
+	}
+	return strings.Join(limits, ", ")
+}
+
+// ticksPerSecond converts Jellyfin ticks (100ns units) to seconds
+const ticksPerSecond = 10000000
+
+// playItem plays an item, or in print-play mode shows the command that
+// would have been run without launching the player
+func (m Model) playItem(item MediaItem) tea.Cmd {
+	args := m.playerCommand(item)
+	if m.printPlay {
+		return func() tea.Msg {
+			return statusMsg("Would run: " + displayCommand(args, m.client.Headers))
+		}
+	}
+	return m.player.play(m.client, item, args)
+}
+
+// Secrets in player arguments: the API key in stream URLs, and the value of
+// a header given on the command line
+var (
+	tokenParam = regexp.MustCompile(`(?i)(api_key=)[^&\s]+`)
+	headerArg  = regexp.MustCompile(`(?i)^(--http-header-fields(?:-append)?=[^:]*:\s*).*$`)
+)
+
+// redactArgs returns a copy of args with API keys and header values
+// replaced, safe to display
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		arg = tokenParam.ReplaceAllString(arg, "${1}REDACTED")
+		redacted[i] = headerArg.ReplaceAllString(arg, "${1}REDACTED")
+	}
+	return redacted
+}
+
+// formatCommand joins argv for display, quoting arguments the way a shell
+// would need them so the command can be copied and run
+func formatCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$&;|<>()*?#`") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
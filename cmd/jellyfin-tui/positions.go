@@ -0,0 +1,102 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Each view remembers the item highlighted when it was left, and highlights
+// it again when the view is fetched anew. Views listing the children of
+// something, like a show's seasons, remember a position per parent.
+
+// positionKey identifies what a view lists
+func (m Model) positionKey(view string) string {
+	switch view {
+	case "seasons", "allepisodes":
+		return view + ":" + m.series.ID
+	case "episodes", "tracks":
+		return view + ":" + m.currentItem.ID
+	case "musicalbums":
+		return view + ":" + m.musicAlbumsParent
+	case "folder":
+		if len(m.folderStack) > 0 {
+			return folderPositionKey(m.folderStack[len(m.folderStack)-1].parentID)
+		}
+	}
+	return view
+}
+
+// folderPositionKey identifies a level of generic library browsing
+func folderPositionKey(parentID string) string {
+	return "folder:" + parentID
+}
+
+// rememberPosition records the highlighted item of the view m shows if next
+// shows something else. A list still showing what it listed for an earlier
+// parent isn't recorded.
+func (m Model) rememberPosition(next Model) {
+	key := m.positionKey(m.currentView)
+	if next.currentView == m.currentView && next.positionKey(next.currentView) == key {
+		return
+	}
+	if m.currentView != "folder" && m.listed[m.currentView] != key {
+		return
+	}
+	if l := m.listForView(m.currentView); l != nil {
+		if item, ok := l.SelectedItem().(MediaItem); ok && item.ID != "" {
+			m.positions[key] = item.ID
+		}
+	}
+}
+
+// restorePosition highlights an item once a view's items arrive: the one
+// saved with the last session, or else the one highlighted when the view
+// was last left. Reloading what the view already lists keeps the cursor
+// where it is, and lists of something new start at the top.
+func (m *Model) restorePosition(view string, l *list.Model) {
+	key := m.positionKey(view)
+	previous := m.listed[view]
+	m.listed[view] = key
+
+	if _, ok := m.pendingSelect[view]; ok {
+		m.selectPending(view, l)
+		return
+	}
+	if previous == key {
+		return
+	}
+	if !selectItem(l, m.positions[key]) {
+		l.Select(0)
+	}
+}
+
+// restoreFolderPosition highlights the item last highlighted in a folder
+// level, which is a new list each time the folder is opened
+func (m *Model) restoreFolderPosition(parentID string, l *list.Model) {
+	selectItem(l, m.positions[folderPositionKey(parentID)])
+}
+
+// selectItem highlights the item with an ID, reporting whether it's listed
+func selectItem(l *list.Model, id string) bool {
+	if id == "" {
+		return false
+	}
+	for i, listItem := range l.Items() {
+		if item, ok := listItem.(MediaItem); ok && item.ID == id {
+			l.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
+// forceRefresh reloads the current view from scratch, forgetting its
+// position so it starts at the top
+func (m Model) forceRefresh() (Model, tea.Cmd) {
+	delete(m.positions, m.positionKey(m.currentView))
+	if l := m.listForView(m.currentView); l != nil {
+		l.Select(0)
+	}
+	m.status = "Reloading..."
+	return m.refreshView()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// prefetchViews are the libraries fetched at startup when that's turned on
+var prefetchViews = []string{"movies", "tvshows", "albums"}
+
+// prefetchTimeout bounds fetching a library at startup, so a stalled server
+// leaves the library to be fetched when it's opened
+const prefetchTimeout = time.Minute
+
+// Prefetch states of a library
+const (
+	prefetchLoading = iota + 1
+	prefetchDone
+)
+
+// prefetchedMsg carries the result of fetching a library at startup: the
+// message its fetch produced
+type prefetchedMsg struct {
+	view string
+	msg  tea.Msg
+}
+
+// prefetchLibraries fetches the movies, TV shows and music libraries in the
+// background, so they're listed as soon as they're opened
+func (m *Model) prefetchLibraries() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, view := range prefetchViews {
+		m.prefetch[view] = prefetchLoading
+		cmds = append(cmds, prefetchLibrary(m.client, view))
+	}
+	return tea.Batch(cmds...)
+}
+
+// Command to fetch one library at startup. The client's concurrency limit
+// keeps the fetches from crowding out the rest of the app.
+func prefetchLibrary(client *jellyfin.Client, view string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), prefetchTimeout)
+		defer cancel()
+
+		fetch := libraryFetch(ctx, client, view)
+		return prefetchedMsg{view: view, msg: fetch()}
+	}
+}
+
+// libraryFetch returns the command that fetches a library's view
+func libraryFetch(ctx context.Context, client *jellyfin.Client, view string) tea.Cmd {
+	switch view {
+	case "movies":
+		return fetchMovies(ctx, client)
+	case "tvshows":
+		return fetchTVShows(ctx, client)
+	}
+	return fetchAlbums(ctx, client)
+}
+
+// openLibrary shows the movies, TV shows or music library. The first visit
+// after startup uses what was prefetched, waiting for it if it's still
+// loading; later visits fetch afresh.
+func (m Model) openLibrary(view string) (Model, tea.Cmd) {
+	m.currentView = view
+	switch m.prefetch[view] {
+	case prefetchDone:
+		delete(m.prefetch, view)
+		return m, nil
+	case prefetchLoading:
+		m.status = "Loading..."
+		return m, nil
+	}
+	ctx := m.viewContext()
+	return m, libraryFetch(ctx, m.client, view)
+}
+
+// setPrefetched lists a prefetched library. A library that couldn't be
+// fetched is left for its view to fetch when opened, which reports the
+// error then; if it's open already, that happens now.
+func (m Model) setPrefetched(msg prefetchedMsg) (Model, tea.Cmd) {
+	waiting := m.currentView == msg.view
+	switch msg.msg.(type) {
+	case nil, errorMsg, statusMsg:
+		delete(m.prefetch, msg.view)
+		if waiting {
+			ctx := m.viewContext()
+			return m, libraryFetch(ctx, m.client, msg.view)
+		}
+		return m, nil
+	}
+
+	if waiting {
+		delete(m.prefetch, msg.view)
+		m.status = ""
+	} else {
+		m.prefetch[msg.view] = prefetchDone
+	}
+	return m, func() tea.Msg { return msg.msg }
+}
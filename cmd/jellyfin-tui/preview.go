@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Images are requested as JPEG
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+const (
+	defaultPreviewWidth  = 30 // Cells
+	defaultPreviewHeight = 22 // Rows
+	minListWidth         = 40 // Room always kept for the list
+	minPreviewWidth      = 12 // Narrower previews are hidden
+	previewGap           = 2  // Cells between the list and the preview
+	previewTimeout       = 10 * time.Second
+	previewCacheSize     = 64 // Rendered previews kept, see imageCache
+)
+
+// previewMsg carries a rendered preview image
+type previewMsg struct {
+	key   string
+	image string
+}
+
+// imageCache keeps rendered previews by previewKey. Past its capacity, the
+// one shown least recently is dropped, so browsing a large library doesn't
+// keep every image it has passed.
+type imageCache struct {
+	capacity int
+	clock    uint64 // Counts uses, to tell which entry is the oldest
+	entries  map[string]*cachedImage
+}
+
+type cachedImage struct {
+	image string
+	used  uint64
+}
+
+func newImageCache(capacity int) *imageCache {
+	return &imageCache{capacity: capacity, entries: map[string]*cachedImage{}}
+}
+
+// get returns a cached image, counting it as used
+func (c *imageCache) get(key string) (string, bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.clock++
+	entry.used = c.clock
+	return entry.image, true
+}
+
+// put caches an image, dropping the least recently used if it's full
+func (c *imageCache) put(key, image string) {
+	c.clock++
+	if entry, ok := c.entries[key]; ok {
+		entry.image, entry.used = image, c.clock
+		return
+	}
+	if len(c.entries) >= c.capacity {
+		var oldest string
+		for k, entry := range c.entries {
+			if oldest == "" || entry.used < c.entries[oldest].used {
+				oldest = k
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	c.entries[key] = &cachedImage{image: image, used: c.clock}
+}
+
+// previewLimits returns the configured maximum preview size in cells
+func (c Config) previewLimits() (width, height int) {
+	width, height = c.PreviewMaxWidth, c.PreviewMaxHeight
+	if width <= 0 {
+		width = defaultPreviewWidth
+	}
+	if height <= 0 {
+		height = defaultPreviewHeight
+	}
+	return width, height
+}
+
+// previewSize fits the preview beside the list in the available space,
+// returning zero when the terminal is too narrow to show it
+func (c Config) previewSize(availWidth, availHeight int) (width, height int) {
+	if !c.ShowPreview {
+		return 0, 0
+	}
+	maxWidth, maxHeight := c.previewLimits()
+	width = min(maxWidth, availWidth-minListWidth-previewGap)
+	height = min(maxHeight, availHeight)
+	if width < minPreviewWidth || height <= 0 {
+		return 0, 0
+	}
+	return width, height
+}
+
+// layout sizes the lists and the preview, or the details panel with the
+// preview as its poster, for the available space
+func (m *Model) layout() {
+	m.detailsWidth = m.config.detailsWidth(m.availWidth)
+	m.listWidth, m.listHeight = m.availWidth, m.availHeight
+	if m.detailsWidth > 0 {
+		maxWidth, maxHeight := m.config.previewLimits()
+		m.previewWidth, m.previewHeight = min(maxWidth, m.detailsWidth), min(maxHeight, m.availHeight/2)
+		m.listWidth -= m.detailsWidth + previewGap
+	} else {
+		m.previewWidth, m.previewHeight = m.config.previewSize(m.availWidth, m.availHeight)
+		if m.previewWidth > 0 {
+			m.listWidth -= m.previewWidth + previewGap
+		}
+	}
+	for _, l := range m.allLists() {
+		l.SetSize(m.listWidth, m.listHeight)
+	}
+}
+
+// previewKey identifies a rendered preview; a resize renders it again, as
+// does new artwork, which comes with a new image tag
+func (m Model) previewKey(id, tag string) string {
+	return fmt.Sprintf("%s:%s@%dx%d", id, tag, m.previewWidth, m.previewHeight)
+}
+
+// previewImage returns the item whose image represents the highlighted item,
+// and that image's tag: tracks show their album's cover
+func previewImage(item MediaItem) (id, tag string) {
+	if item.Type == "track" && item.ParentID != "" {
+		return item.ParentID, item.ImageTag
+	}
+	return item.ID, item.ImageTag
+}
+
+// loadPreview fetches the image of the highlighted item if it isn't cached
+func (m Model) loadPreview() (Model, tea.Cmd) {
+	if m.previewWidth == 0 {
+		return m, nil
+	}
+	l := m.listForView(m.currentView)
+	if l == nil {
+		return m, nil
+	}
+	item, ok := l.SelectedItem().(MediaItem)
+	if !ok || item.ID == "" {
+		m.previewID, m.previewTag = "", ""
+		return m, nil
+	}
+
+	m.previewID, m.previewTag = previewImage(item)
+	key := m.previewKey(m.previewID, m.previewTag)
+	if _, ok := m.previewCache.get(key); ok {
+		return m, nil
+	}
+	m.previewCache.put(key, "") // Loading; shown as blank until it arrives
+	return m, fetchPreview(m.client, m.previewID, m.previewTag, key, m.previewWidth, m.previewHeight)
+}
+
+// previewView renders the preview panel for the highlighted item
+func (m Model) previewView() string {
+	if m.previewWidth == 0 || m.previewID == "" {
+		return ""
+	}
+	image, _ := m.previewCache.get(m.previewKey(m.previewID, m.previewTag))
+	return image
+}
+
+// Command to fetch and render an item's image to fit width x height cells.
+// Items without an image render as an empty preview.
+func fetchPreview(client *jellyfin.Client, itemID, tag, key string, width, height int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+		defer cancel()
+
+		// Each cell shows two pixels stacked vertically
+		data, err := client.GetImage(ctx, itemID, tag, width, height*2)
+		if err != nil {
+			return previewMsg{key: key}
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return previewMsg{key: key}
+		}
+		return previewMsg{key: key, image: renderImage(img, width, height)}
+	}
+}
+
+// renderImage draws an image in at most width x height cells using half
+// blocks, the upper pixel as the foreground and the lower as the background
+func renderImage(img image.Image, width, height int) string {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return ""
+	}
+
+	// Fit within the cells, keeping the aspect ratio
+	pixelsWide, pixelsHigh := width, width*bounds.Dy()/bounds.Dx()
+	if pixelsHigh > height*2 {
+		pixelsWide, pixelsHigh = height*2*bounds.Dx()/bounds.Dy(), height*2
+	}
+	if pixelsWide == 0 || pixelsHigh < 2 {
+		return ""
+	}
+
+	// Nearest neighbour sampling is plenty at this size
+	at := func(x, y int) (r, g, b uint32) {
+		r, g, b, _ = img.At(bounds.Min.X+x*bounds.Dx()/pixelsWide, bounds.Min.Y+y*bounds.Dy()/pixelsHigh).RGBA()
+		return r >> 8, g >> 8, b >> 8
+	}
+
+	var sb strings.Builder
+	for y := 0; y+1 < pixelsHigh; y += 2 {
+		for x := 0; x < pixelsWide; x++ {
+			tr, tg, tb := at(x, y)
+			br, bg, bb := at(x, y+1)
+			fmt.Fprintf(&sb, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", tr, tg, tb, br, bg, bb)
+		}
+		sb.WriteString("\x1b[0m\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestPreviewKeyIncludesTag(t *testing.T) {
+	m := Model{previewWidth: 40, previewHeight: 20}
+	old, changed := m.previewKey("item", "v1"), m.previewKey("item", "v2")
+	if old == changed {
+		t.Errorf("previewKey is %q for both image tags; a changed image would show the cached one", old)
+	}
+	if m.previewKey("item", "v1") != old {
+		t.Error("previewKey differs for the same item and tag")
+	}
+}
+
+func TestImageCacheDropsLeastRecentlyUsed(t *testing.T) {
+	c := newImageCache(2)
+	c.put("a", "A")
+	c.put("b", "B")
+	c.get("a") // b is now the least recently used
+	c.put("c", "C")
+
+	if _, ok := c.get("b"); ok {
+		t.Error("b is still cached; it was the least recently used")
+	}
+	for key, want := range map[string]string{"a": "A", "c": "C"} {
+		if got, ok := c.get(key); !ok || got != want {
+			t.Errorf("get(%q) = %q, %v; want %q, true", key, got, ok, want)
+		}
+	}
+
+	c.put("a", "A2") // Replacing an entry doesn't drop another
+	if len(c.entries) != 2 {
+		t.Errorf("%d entries after replacing one, want 2", len(c.entries))
+	}
+}
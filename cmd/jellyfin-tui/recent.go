@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// recentLimit is how many recently played items are fetched
+const recentLimit = 50
+
+type fetchRecentMsg []MediaItem
+
+// playMsg plays an item once a question about it has been answered
+type playMsg MediaItem
+
+// openRecent lists what was played most recently
+func (m Model) openRecent() (Model, tea.Cmd) {
+	m.returnTo["recent"] = m.currentView
+	m.currentView = "recent"
+	ctx := m.viewContext()
+	return m, fetchRecent(ctx, m.client)
+}
+
+// updateRecent handles input in the recently played view. Items left
+// partway through offer to resume.
+func (m Model) updateRecent(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.recentList, cmd = m.recentList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" && m.recentList.FilterState() != list.Filtering {
+		if selectedItem, ok := m.recentList.SelectedItem().(MediaItem); ok && selectedItem.ID != "" {
+			return m.offerResume(selectedItem)
+		}
+	}
+
+	return m, cmd
+}
+
+// offerResume asks whether to resume an item left partway through or start
+// it over, and plays anything else straight away
+func (m Model) offerResume(item MediaItem) (Model, tea.Cmd) {
+	ticks := item.UserData.PlaybackPositionTicks
+	if ticks <= 0 || item.StreamURL == "" {
+		return m.drillInto(item)
+	}
+
+	fromStart := item
+	fromStart.UserData.PlaybackPositionTicks = 0
+	return m.askChoice(fmt.Sprintf("Resume %s from %s? n starts over", item.Title(), formatDuration(ticks)),
+		playLater(item), playLater(fromStart))
+}
+
+// Command to play an item after a question
+func playLater(item MediaItem) tea.Cmd {
+	return func() tea.Msg {
+		return playMsg(item)
+	}
+}
+
+// Command to fetch recently played items, described with when they were
+// last played
+func fetchRecent(ctx context.Context, client *jellyfin.Client) tea.Cmd {
+	return func() tea.Msg {
+		items, err := client.GetRecentlyPlayed(ctx, recentLimit)
+		if err != nil {
+			return fetchError(err)
+		}
+
+		now := time.Now()
+		mediaItems := convertItems(client, items)
+		for i, item := range items {
+			mediaItems[i].DisplayTitle = searchTitle(item)
+			if played, ok := item.UserData.LastPlayed(); ok {
+				mediaItems[i].DisplayDesc = fmt.Sprintf("%s · played %s", item.Type, formatPlayedDate(played, now))
+			}
+		}
+		return fetchRecentMsg(mediaItems)
+	}
+}
+
+// formatPlayedDate formats when something was played in local time, e.g.
+// "today 21:04", "yesterday 09:30", "Mar 3 18:12" or "Dec 24, 2023"
+func formatPlayedDate(played, now time.Time) string {
+	played, now = played.Local(), now.Local()
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	switch {
+	case !played.Before(today):
+		return "today " + played.Format("15:04")
+	case !played.Before(today.AddDate(0, 0, -1)):
+		return "yesterday " + played.Format("15:04")
+	case played.Year() == year:
+		return played.Format("Jan 2 15:04")
+	}
+	return played.Format("Jan 2, 2006")
+}
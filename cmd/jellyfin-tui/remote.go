@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// SessionItem is another device shown in the "Play on…" view
+type SessionItem struct {
+	jellyfin.Session
+}
+
+func (s SessionItem) Title() string {
+	if s.DeviceName != "" {
+		return s.DeviceName
+	}
+	return s.Client
+}
+
+// Description shows the app, user and what's playing, e.g.
+// "Jellyfin Android TV · alice · playing Alien"
+func (s SessionItem) Description() string {
+	var parts []string
+	if s.Client != "" {
+		parts = append(parts, s.Client)
+	}
+	if s.UserName != "" {
+		parts = append(parts, s.UserName)
+	}
+	if s.NowPlayingItem != nil {
+		parts = append(parts, "playing "+s.NowPlayingItem.Name)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (s SessionItem) FilterValue() string { return s.DeviceName }
+
+// fetchSessionsMsg carries the sessions an item can be played on
+type fetchSessionsMsg []jellyfin.Session
+
+// openSessions lists the other devices the highlighted item can be played on
+func (m Model) openSessions(item MediaItem) (Model, tea.Cmd) {
+	m.castItem = item
+	m.returnTo["sessions"] = m.currentView
+	m.sessionsList.SetItems([]list.Item{})
+	m.sessionsList.Title = fmt.Sprintf("Play %s on…", item.Title())
+	m.currentView = "sessions"
+	m.status = "Looking for devices..."
+	ctx := m.viewContext()
+	return m, fetchSessions(ctx, m.client)
+}
+
+// setSessions shows the fetched sessions
+func (m Model) setSessions(sessions fetchSessionsMsg) (Model, tea.Cmd) {
+	items := make([]list.Item, len(sessions))
+	for i, session := range sessions {
+		items[i] = SessionItem{session}
+	}
+	m.sessionsList.SetItems(items)
+	if len(sessions) == 0 {
+		m.status = "No other devices can be controlled; open a Jellyfin app on one first"
+	} else {
+		m.status = ""
+	}
+	return m, nil
+}
+
+// updateSessions handles input in the "Play on…" view
+func (m Model) updateSessions(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.sessionsList, cmd = m.sessionsList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" &&
+		m.sessionsList.FilterState() != list.Filtering {
+		if selected, ok := m.sessionsList.SelectedItem().(SessionItem); ok {
+			m.currentView = m.returnTo["sessions"]
+			return m, playOnSession(m.client, selected.Session, m.castItem)
+		}
+	}
+
+	return m, cmd
+}
+
+// Command to fetch the sessions that can be remote controlled
+func fetchSessions(ctx context.Context, client *jellyfin.Client) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := client.GetSessions(ctx)
+		if err != nil {
+			return fetchError(err)
+		}
+		return fetchSessionsMsg(sessions)
+	}
+}
+
+// Command to play an item on another session instead of in mpv
+func playOnSession(client *jellyfin.Client, session jellyfin.Session, item MediaItem) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		defer cancel()
+
+		device := SessionItem{session}.Title()
+		if err := client.SendPlayCommand(ctx, session.ID, item.ID); err != nil {
+			return statusMsg(fmt.Sprintf("Couldn't play on %s: %v", device, err))
+		}
+		return statusMsg(fmt.Sprintf("Playing %s on %s", item.Title(), device))
+	}
+}
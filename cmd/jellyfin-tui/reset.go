@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// localState is what the app keeps in its config directory besides the
+// config itself, by file name. Images and other fetched data are only
+// cached in memory, so there's nothing else on disk.
+var localState = []struct{ name, description string }{
+	{"session", "last session"},
+	{"search_history", "search history"},
+}
+
+// runReset removes the app's local state, then offers to remove the config
+// too, reading the answer from in. Only the known files in the app's config
+// directory are removed. It returns the process exit code.
+func runReset(w io.Writer, in io.Reader) int {
+	configDir, err := appConfigDir()
+	if err != nil {
+		fmt.Fprintf(w, "Error: %v\n", err)
+		return 1
+	}
+
+	removed, failed := 0, false
+	remove := func(name, description string) {
+		path := filepath.Join(configDir, name)
+		err := os.Remove(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			fmt.Fprintf(w, "Couldn't remove %s (%s): %v\n", path, description, err)
+			failed = true
+		default:
+			fmt.Fprintf(w, "Removed %s (%s)\n", path, description)
+			removed++
+		}
+	}
+
+	for _, state := range localState {
+		remove(state.name, state.description)
+	}
+
+	configFile := filepath.Join(configDir, "config")
+	if _, err := os.Stat(configFile); err == nil {
+		fmt.Fprintf(w, "Also remove the config at %s, with the server URL and API key? [y/N] ", configFile)
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		if strings.EqualFold(strings.TrimSpace(answer), "y") {
+			remove("config", "config")
+		} else {
+			fmt.Fprintln(w, "Kept the config")
+		}
+	}
+
+	if removed == 0 && !failed {
+		fmt.Fprintf(w, "Nothing removed from %s\n", configDir)
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
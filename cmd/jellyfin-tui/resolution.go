@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// resolutionFilters are the choices the resolution filter cycles through;
+// "" shows everything
+var resolutionFilters = []string{"", "4K", "1080p", "720p", "SD"}
+
+// resolutionBadge names a video's resolution, or returns "" if it isn't
+// known. Widths are checked too so that widescreen films, which are
+// shorter than their resolution's nominal height, still get its name.
+func resolutionBadge(width, height int) string {
+	switch {
+	case width <= 0 && height <= 0:
+		return ""
+	case width >= 3200 || height >= 1800:
+		return "4K"
+	case width >= 1800 || height >= 1000:
+		return "1080p"
+	case width >= 1200 || height >= 700:
+		return "720p"
+	}
+	return "SD"
+}
+
+// nextResolutionFilter returns the filter after the given one
+func nextResolutionFilter(current string) string {
+	for i, resolution := range resolutionFilters {
+		if resolution == current {
+			return resolutionFilters[(i+1)%len(resolutionFilters)]
+		}
+	}
+	return ""
+}
+
+// filterByResolution returns the items of a resolution, or every item if
+// resolution is "". Items whose resolution isn't known are left out.
+func filterByResolution(items []MediaItem, resolution string) []list.Item {
+	if resolution == "" {
+		return convertToListItems(items)
+	}
+	var filtered []MediaItem
+	for _, item := range items {
+		if item.Resolution == resolution {
+			filtered = append(filtered, item)
+		}
+	}
+	return convertToListItems(filtered)
+}
+
+// applyResolutionFilter refills the movies and episodes lists from their
+// fetched items
+func (m *Model) applyResolutionFilter() {
+	suffix := ""
+	if m.resolutionFilter != "" {
+		suffix = " · " + m.resolutionFilter + " only"
+	}
+	m.moviesList.Title = "Movies" + suffix
+	m.episodesList.Title = "Episodes" + suffix
+	m.moviesList.SetItems(filterByResolution(m.movieItems, m.resolutionFilter))
+	m.episodesList.SetItems(filterByResolution(m.episodeItems, m.resolutionFilter))
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// searchPageSize is how many results are fetched at a time; the next page
+// is fetched when the last result is highlighted
+const searchPageSize = 50
+
+// searchResultsMsg carries a page of results for a query, or why it
+// couldn't be fetched
+type searchResultsMsg struct {
+	query      string
+	startIndex int
+	items      []MediaItem
+	total      int // Matches across all pages
+	err        error
+}
+
+// openSearch shows an empty search box
+func (m Model) openSearch() (Model, tea.Cmd) {
+	m.currentView = "search"
+	m.searchInput.SetValue("")
+	m.searchInput.Focus()
+	m.searchQuery, m.searchTotal, m.searchLoading = "", 0, false
+	m.searchItems = nil
+	m.searchList.SetItems([]list.Item{})
+	m.historyIndex = -1
+	return m, nil
+}
+
+// typingSearch reports whether key presses are going to the search box
+func (m Model) typingSearch() bool {
+	return m.currentView == "search" && m.searchInput.Focused()
+}
+
+// startSearch runs a new query, abandoning pages still loading for the last
+func (m Model) startSearch(query string) (Model, tea.Cmd) {
+	m.searchQuery, m.searchTotal, m.searchLoading = query, 0, true
+	m.searchItems = nil
+	m.searchList.SetItems([]list.Item{})
+	m.searchInput.Blur()
+	m.historyIndex = -1
+	m.status = "Searching..."
+
+	m.searchHistory = rememberSearch(m.searchHistory, query)
+	if err := saveSearchHistory(m.searchHistory); err != nil {
+		m.status = fmt.Sprintf("Searching... (%v)", err)
+	}
+
+	ctx := m.viewContext()
+	return m, searchMedia(ctx, m.client, query, 0)
+}
+
+// setSearchResults adds a page of results, ignoring pages for an earlier
+// query. A failed page is reported on the status line.
+func (m Model) setSearchResults(msg searchResultsMsg) (Model, tea.Cmd) {
+	if msg.query != m.searchQuery {
+		return m, nil
+	}
+	m.searchLoading = false
+	if msg.err != nil {
+		// Reaching the end of the list again retries a later page
+		if msg.startIndex > 0 {
+			m.status = fmt.Sprintf("Couldn't load more results: %v", msg.err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Search for %q failed: %v", msg.query, msg.err)
+		m.searchInput.Focus()
+		return m, nil
+	}
+	m.searchTotal = msg.total
+	m.status = ""
+
+	if msg.startIndex == 0 && len(msg.items) == 0 {
+		m.status = fmt.Sprintf("Nothing found for %q", msg.query)
+		m.searchInput.Focus()
+		return m, nil
+	}
+	m.searchItems = append(m.searchItems, msg.items...)
+	m.showSearchResults()
+	if item, ok := m.quickPlayMatch(msg); ok {
+		return m.startPlayback(item)
+	}
+	return m, nil
+}
+
+// quickPlayMatch returns the result to play without browsing the results,
+// if quick play is on: the only result, when it's playable and its title is
+// the query, ignoring case. It resumes from the saved position as usual.
+func (m Model) quickPlayMatch(msg searchResultsMsg) (MediaItem, bool) {
+	if !m.config.QuickPlaySearch || msg.startIndex != 0 || msg.total != 1 || len(msg.items) != 1 {
+		return MediaItem{}, false
+	}
+	item := msg.items[0]
+	if item.IsFolder || item.StreamURL == "" ||
+		!strings.EqualFold(strings.TrimSpace(item.ItemTitle), strings.TrimSpace(msg.query)) {
+		return MediaItem{}, false
+	}
+	return item, true
+}
+
+// showSearchResults lists the loaded results, grouped by type if that's
+// turned on, keeping the highlighted result
+func (m *Model) showSearchResults() {
+	selected, _ := m.searchList.SelectedItem().(MediaItem)
+	if m.config.GroupSearchResults {
+		m.searchList.SetItems(groupByType(m.searchItems))
+	} else {
+		m.searchList.SetItems(convertToListItems(m.searchItems))
+	}
+	selectItem(&m.searchList, selected.ID)
+	skipHeaders(&m.searchList, false)
+	m.searchList.Title = m.searchResultsTitle()
+}
+
+// searchGroups are the headers results are grouped under, in order, by
+// Jellyfin item type. Other types go under "Other" at the end.
+var searchGroups = []struct{ itemType, title string }{
+	{"Movie", "Movies"},
+	{"Series", "Series"},
+	{"Season", "Seasons"},
+	{"Episode", "Episodes"},
+	{"BoxSet", "Collections"},
+	{"MusicArtist", "Artists"},
+	{"MusicAlbum", "Albums"},
+	{"Audio", "Songs"},
+	{"AudioBook", "Audiobooks"},
+	{"Book", "Books"},
+}
+
+// groupByType lists results under a header for each type, with its count
+func groupByType(items []MediaItem) []list.Item {
+	byType := map[string][]MediaItem{}
+	for _, item := range items {
+		byType[item.ItemType] = append(byType[item.ItemType], item)
+	}
+
+	var listItems []list.Item
+	addGroup := func(title string, group []MediaItem) {
+		if len(group) > 0 {
+			listItems = append(listItems, sectionHeader{title: title, count: len(group)})
+			listItems = append(listItems, convertToListItems(group)...)
+		}
+	}
+	for _, group := range searchGroups {
+		addGroup(group.title, byType[group.itemType])
+		delete(byType, group.itemType)
+	}
+
+	// Keep the rest in the order the server returned them
+	var other []MediaItem
+	for _, item := range items {
+		if _, ok := byType[item.ItemType]; ok {
+			other = append(other, item)
+		}
+	}
+	addGroup("Other", other)
+	return listItems
+}
+
+// toggleSearchGrouping switches between grouped and flat results and saves
+// the choice
+func (m Model) toggleSearchGrouping() (Model, tea.Cmd) {
+	m.config.GroupSearchResults = !m.config.GroupSearchResults
+	m.showSearchResults()
+	if err := saveConfig(m.config); err != nil {
+		m.status = fmt.Sprintf("Failed to save grouping preference: %v", err)
+	}
+	return m, nil
+}
+
+// searchResultsTitle counts the results shown and those left to load
+func (m Model) searchResultsTitle() string {
+	shown := len(m.searchItems)
+	if shown < m.searchTotal {
+		return fmt.Sprintf("Search Results: %d of %d (scroll to the end for more)", shown, m.searchTotal)
+	}
+	return fmt.Sprintf("Search Results: %d", shown)
+}
+
+// updateSearch handles input in the search view: typing a query, then
+// browsing its results. esc from the results returns to the search box.
+func (m Model) updateSearch(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if m.searchInput.Focused() {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "enter":
+				if query := m.searchInput.Value(); query != "" {
+					return m.startSearch(query)
+				}
+				return m, nil
+			case "up", "down":
+				if m.showingHistory() {
+					m.recallSearch(keyMsg.String() == "up")
+					return m, nil
+				}
+			}
+		}
+		m.searchInput, cmd = m.searchInput.Update(msg)
+		if m.historyIndex >= 0 && m.searchInput.Value() != m.searchHistory[m.historyIndex] {
+			// Editing a recalled search starts a new one
+			m.historyIndex = -1
+		}
+		return m, cmd
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "t" && m.searchList.FilterState() != list.Filtering {
+		return m.toggleSearchGrouping()
+	}
+
+	m.searchList, cmd = m.searchList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" && m.searchList.FilterState() != list.Filtering {
+		if selectedItem, ok := m.searchList.SelectedItem().(MediaItem); ok && selectedItem.ID != "" {
+			return m.drillInto(selectedItem)
+		}
+	}
+
+	more := m.loadMoreResults()
+	return m, tea.Batch(cmd, more)
+}
+
+// showingHistory reports whether recent searches are offered: while the
+// search box is empty, or holds the one recalled from them
+func (m Model) showingHistory() bool {
+	return len(m.searchHistory) > 0 && (m.searchInput.Value() == "" || m.historyIndex >= 0)
+}
+
+// recallSearch fills the search box with the previous (older) or next recent
+// search, like a shell's history; going past the newest empties the box
+func (m *Model) recallSearch(older bool) {
+	switch {
+	case older && m.historyIndex+1 < len(m.searchHistory):
+		m.historyIndex++
+	case !older && m.historyIndex >= 0:
+		m.historyIndex--
+	default:
+		return
+	}
+
+	if m.historyIndex < 0 {
+		m.searchInput.SetValue("")
+		return
+	}
+	m.searchInput.SetValue(m.searchHistory[m.historyIndex])
+	m.searchInput.CursorEnd()
+}
+
+// searchBoxView renders the search box, with recent searches beneath it
+func (m Model) searchBoxView() string {
+	view := fmt.Sprintf("Search: %s\n\nType a search query and press Enter", m.searchInput.View())
+	if !m.showingHistory() {
+		return view
+	}
+
+	var sb strings.Builder
+	sb.WriteString(view + "\n\nRecent searches (↑/↓ to pick):\n")
+	for i, query := range m.searchHistory {
+		marker := "  "
+		if i == m.historyIndex {
+			marker = "> "
+		}
+		sb.WriteString("\n" + marker + query)
+	}
+	return sb.String()
+}
+
+// loadMoreResults fetches the next page once the last result is highlighted
+func (m *Model) loadMoreResults() tea.Cmd {
+	loaded := len(m.searchItems)
+	if loaded == 0 || loaded >= m.searchTotal ||
+		m.searchList.FilterState() != list.Unfiltered || m.searchList.Index() < len(m.searchList.Items())-1 {
+		return nil
+	}
+
+	// Opening a result cancels the search's context, along with any page
+	// that was loading; it's fetched again under a new one
+	ctx := m.fetchCtx
+	if ctx == nil || ctx.Err() != nil {
+		ctx = m.viewContext()
+	} else if m.searchLoading {
+		return nil
+	}
+
+	m.searchLoading = true
+	m.status = "Loading more results..."
+	return searchMedia(ctx, m.client, m.searchQuery, loaded)
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Session records where the user was when the app last exited
+type Session struct {
+	View        string `json:"view"`
+	ItemID      string `json:"item_id,omitempty"`
+	ParentID    string `json:"parent_id,omitempty"`    // Series, season or album the view lists
+	ParentTitle string `json:"parent_title,omitempty"` // Used to title the restored list
+	SeriesID    string `json:"series_id,omitempty"`    // Series owning the season, for episodes
+}
+
+// restoreFailedMsg is sent when a restored view's fetch fails
+type restoreFailedMsg struct{ err error }
+
+// loadSession loads the last session from ~/.config/jellyfin-tui/session
+func loadSession() (Session, error) {
+	configDir, err := appConfigDir()
+	if err != nil {
+		return Session{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "session"))
+	if err != nil {
+		return Session{}, fmt.Errorf("failed to read session file: %v", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return Session{}, fmt.Errorf("failed to parse session file: %v", err)
+	}
+
+	return session, nil
+}
+
+// saveSession saves the session to ~/.config/jellyfin-tui/session
+func saveSession(session Session) error {
+	configDir, err := appConfigDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(configDir, "session"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write session file: %v", err)
+	}
+
+	return nil
+}
+
+// listForView returns the list backing a view, or nil for views without one
+func (m *Model) listForView(view string) *list.Model {
+	switch view {
+	case "main":
+		return &m.mainList
+	case "movies":
+		return &m.moviesList
+	case "tvshows":
+		return &m.tvShowsList
+	case "seasons":
+		return &m.seasonsList
+	case "episodes":
+		return &m.episodesList
+	case "albums":
+		return &m.albumsList
+	case "tracks":
+		return &m.tracksList
+	case "latest":
+		return &m.latestList
+	case "dashboard":
+		return &m.dashboardList
+	case "allepisodes":
+		return &m.allEpisodesList
+	case "allmedia":
+		return &m.allMediaList
+	case "recent":
+		return &m.recentList
+	case "genres":
+		return &m.genresList
+	case "artists":
+		return &m.artistsList
+	case "musicalbums":
+		return &m.musicAlbumsList
+	case "search":
+		return &m.searchList
+	case "folder":
+		if len(m.folderStack) > 0 {
+			return &m.folderStack[len(m.folderStack)-1].list
+		}
+	}
+	return nil
+}
+
+// currentSession captures the current view and highlighted item
+func (m Model) currentSession() Session {
+	session := Session{View: m.currentView}
+
+	switch m.currentView {
+	case "movies", "tvshows", "albums":
+	case "seasons":
+		session.ParentID = m.series.ID
+		session.ParentTitle = m.series.ItemTitle
+	case "episodes", "tracks":
+		session.ParentID = m.currentItem.ID
+		session.ParentTitle = m.currentItem.ItemTitle
+		session.SeriesID = m.currentItem.ParentID
+	default:
+		// Search results and forms aren't worth restoring
+		return Session{View: "main"}
+	}
+
+	if l := m.listForView(m.currentView); l != nil {
+		if item, ok := l.SelectedItem().(MediaItem); ok {
+			session.ItemID = item.ID
+		}
+	}
+
+	return session
+}
+
+// applySession switches the model to the saved view; the fetches are
+// dispatched from Init
+func (m *Model) applySession(session Session) {
+	switch session.View {
+	case "movies", "tvshows", "albums":
+	case "seasons", "tracks":
+		if session.ParentID == "" {
+			return
+		}
+	case "episodes":
+		if session.ParentID == "" || session.SeriesID == "" {
+			return
+		}
+	default:
+		return
+	}
+
+	m.currentView = session.View
+	m.viewContext()
+	m.pendingSelect = map[string]string{session.View: session.ItemID}
+
+	switch session.View {
+	case "seasons":
+		m.currentItem = MediaItem{ID: session.ParentID, ItemTitle: session.ParentTitle, Type: "tvshow"}
+		m.series = m.currentItem
+		m.pendingSelect["tvshows"] = session.ParentID
+	case "episodes":
+		m.currentItem = MediaItem{ID: session.ParentID, ItemTitle: session.ParentTitle, Type: "season", ParentID: session.SeriesID}
+		m.series = MediaItem{ID: session.SeriesID, Type: "tvshow"}
+		m.pendingSelect["seasons"] = session.ParentID
+		m.pendingSelect["tvshows"] = session.SeriesID
+	case "tracks":
+		m.currentItem = MediaItem{ID: session.ParentID, ItemTitle: session.ParentTitle, Type: "album"}
+		m.tracksList.Title = session.ParentTitle
+		m.pendingSelect["albums"] = session.ParentID
+	}
+
+	m.initCmd = m.restoreCmd(session)
+}
+
+// restoreCmd fetches the restored view along with the views above it, so
+// that esc still navigates back up the hierarchy
+func (m Model) restoreCmd(session Session) tea.Cmd {
+	var cmds []tea.Cmd
+	switch session.View {
+	case "movies":
+		cmds = append(cmds, fetchMovies(m.fetchCtx, m.client))
+	case "tvshows":
+		cmds = append(cmds, fetchTVShows(m.fetchCtx, m.client))
+	case "seasons":
+		cmds = append(cmds, fetchTVShows(m.fetchCtx, m.client), fetchSeasons(m.fetchCtx, m.client, session.ParentID))
+	case "episodes":
+		cmds = append(cmds, fetchTVShows(m.fetchCtx, m.client), fetchSeasons(m.fetchCtx, m.client, session.SeriesID),
+			fetchEpisodes(m.fetchCtx, m.client, session.ParentID))
+	case "albums":
+		cmds = append(cmds, fetchAlbums(m.fetchCtx, m.client))
+	case "tracks":
+		cmds = append(cmds, fetchAlbums(m.fetchCtx, m.client), fetchTracks(m.fetchCtx, m.client, session.ParentID))
+	}
+
+	for i, cmd := range cmds {
+		cmds[i] = asRestore(cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// asRestore turns a fetch error into a restoreFailedMsg so a stale session
+// falls back to the main menu instead of the error screen
+func asRestore(cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg := cmd()
+		if err, ok := msg.(errorMsg); ok {
+			return restoreFailedMsg{err: err}
+		}
+		return msg
+	}
+}
+
+// selectPending highlights the item saved for a view, if it's still present
+func (m *Model) selectPending(view string, l *list.Model) {
+	id, ok := m.pendingSelect[view]
+	if !ok {
+		return
+	}
+	delete(m.pendingSelect, view)
+
+	for i, listItem := range l.Items() {
+		if item, ok := listItem.(MediaItem); ok && item.ID == id {
+			l.Select(i)
+			return
+		}
+	}
+	// The item no longer exists; leave the cursor at the top
+}
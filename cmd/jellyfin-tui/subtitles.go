@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// defaultSubtitleLanguage is used when the config doesn't set one
+const defaultSubtitleLanguage = "eng"
+
+// subtitleDownloadTimeout bounds a subtitle download, which the server makes
+// from its provider before answering
+const subtitleDownloadTimeout = 30 * time.Second
+
+// SubtitleItem is a remote subtitle shown in the subtitles view
+type SubtitleItem struct {
+	jellyfin.RemoteSubtitle
+}
+
+func (s SubtitleItem) Title() string { return s.Name }
+
+func (s SubtitleItem) Description() string {
+	parts := []string{s.ProviderName}
+	if s.Format != "" {
+		parts = append(parts, s.Format)
+	}
+	parts = append(parts, fmt.Sprintf("%d downloads", s.DownloadCount))
+	if s.IsHashMatch {
+		parts = append(parts, "exact match")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (s SubtitleItem) FilterValue() string { return s.Name }
+
+type subtitleResultsMsg []jellyfin.RemoteSubtitle
+type subtitleDownloadedMsg string
+
+// subtitleLanguage returns the configured subtitle search language
+func (c Config) subtitleLanguage() string {
+	if c.SubtitleLanguage != "" {
+		return c.SubtitleLanguage
+	}
+	return defaultSubtitleLanguage
+}
+
+// openSubtitles switches to the subtitles view and searches for subtitles for
+// the highlighted item, returning to the current view on esc
+func (m Model) openSubtitles(item MediaItem) (Model, tea.Cmd) {
+	m.subtitleItem = item
+	m.subtitleReturnView = m.currentView
+	m.subtitlesList.SetItems([]list.Item{})
+	m.subtitlesList.Title = fmt.Sprintf("Subtitles for %s (%s)", item.Title(), m.config.subtitleLanguage())
+	m.currentView = "subtitles"
+	m.status = "Searching for subtitles..."
+	ctx := m.viewContext()
+	return m, searchSubtitles(ctx, m.client, item.ID, m.config.subtitleLanguage())
+}
+
+// updateSubtitles handles input in the subtitles view
+func (m Model) updateSubtitles(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.subtitlesList, cmd = m.subtitlesList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" &&
+		m.subtitlesList.FilterState() != list.Filtering {
+		if selected, ok := m.subtitlesList.SelectedItem().(SubtitleItem); ok {
+			m.status = fmt.Sprintf("Downloading %s...", selected.Name)
+			return m, downloadSubtitle(m.client, m.subtitleItem.ID, selected.ID)
+		}
+	}
+
+	return m, cmd
+}
+
+// Command to search the server's subtitle providers
+func searchSubtitles(ctx context.Context, client *jellyfin.Client, itemID, lang string) tea.Cmd {
+	return func() tea.Msg {
+		subtitles, err := client.SearchSubtitles(ctx, itemID, lang)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		if err != nil {
+			return statusMsg(subtitleErrorText(err))
+		}
+		return subtitleResultsMsg(subtitles)
+	}
+}
+
+// Command to have the server download a subtitle for an item
+func downloadSubtitle(client *jellyfin.Client, itemID, subtitleID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), subtitleDownloadTimeout)
+		defer cancel()
+
+		if err := client.DownloadSubtitle(ctx, itemID, subtitleID); err != nil {
+			return statusMsg(subtitleErrorText(err))
+		}
+		return subtitleDownloadedMsg(subtitleID)
+	}
+}
+
+// subtitleErrorText describes a subtitle failure for the status line
+func subtitleErrorText(err error) string {
+	if errors.Is(err, jellyfin.ErrSubtitlesUnsupported) {
+		return "Subtitles unavailable: this server has no subtitle provider plugin installed"
+	}
+	return fmt.Sprintf("Subtitle request failed: %v", err)
+}
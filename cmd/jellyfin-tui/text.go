@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ellipsis ends text cut short to fit
+const ellipsis = "…"
+
+// displayText makes a title or description from the server safe to lay out
+// on one line. Newlines, tabs and other control characters become spaces,
+// and bidirectional embeddings, overrides and isolates are dropped: left
+// unbalanced, they reverse the rest of the line in terminals that honour
+// them. Right-to-left text itself is kept.
+func displayText(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '\u202a' && r <= '\u202e', r >= '\u2066' && r <= '\u2069':
+			return -1
+		case r < ' ', r >= '\u007f' && r < '\u00a0':
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// truncateText shortens text to fit in width terminal cells, ending it with
+// an ellipsis. Width is measured per grapheme, so wide characters such as
+// CJK count as two cells and combining marks as none, and a wide character
+// is never split.
+func truncateText(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return ansi.Truncate(s, width, ellipsis)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{"fits", "Alien", 10, "Alien"},
+		{"exact fit", "Alien", 5, "Alien"},
+		{"cut", "The Lord of the Rings", 10, "The Lord …"},
+		{"no room", "Alien", 0, ""},
+		{"CJK fits", "千と千尋", 8, "千と千尋"},
+		{"CJK cut on a boundary", "千と千尋の神隠し", 7, "千と千…"},
+		{"CJK cut mid-character", "千と千尋の神隠し", 6, "千と…"},
+		{"CJK mixed", "Akira アキラ", 9, "Akira ア…"},
+		{"combining marks", "Amélie Poulain", 6, "Améli…"},
+		{"RTL", "الرسالة الكاملة", 8, "الرسالة…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateText(tt.text, tt.width)
+			if got != tt.want {
+				t.Errorf("truncateText(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+			if w := ansi.StringWidth(got); w > tt.width {
+				t.Errorf("truncateText(%q, %d) is %d cells wide", tt.text, tt.width, w)
+			}
+		})
+	}
+}
+
+func TestDisplayText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "Alien", "Alien"},
+		{"newlines and tabs", "Part 1\nThe\tBeginning", "Part 1 The Beginning"},
+		{"C1 control", "Alien\u0085Resurrection", "Alien Resurrection"},
+		{"RTL kept", "الرسالة", "الرسالة"},
+		{"RTL override dropped", "\u202eevil.mkv", "evil.mkv"},
+		{"unbalanced embedding dropped", "\u202bשלום world", "שלום world"},
+		{"isolates dropped", "\u2067עברית\u2069 title", "עברית title"},
+		{"marks kept", "a\u200fb", "a\u200fb"}, // A mark only directs the characters beside it
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayText(tt.text); got != tt.want {
+				t.Errorf("displayText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newEpisodesStyle highlights the badge of series with unwatched episodes
+var newEpisodesStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Bold(true)
+
+// newEpisodes returns how many episodes of a series are unwatched; other
+// items, and fully watched series, have none
+func newEpisodes(item MediaItem) int {
+	if item.ItemType != "Series" || item.UserData.Played {
+		return 0
+	}
+	return item.UserData.UnplayedItemCount
+}
+
+// newEpisodesBadge marks a series with its unwatched episodes, e.g. "(3 new)"
+func newEpisodesBadge(item MediaItem) string {
+	count := newEpisodes(item)
+	if count == 0 {
+		return ""
+	}
+	return newEpisodesStyle.Render(fmt.Sprintf("(%d new)", count))
+}
+
+// setTVShows lists the shows, those with unwatched episodes first if
+// that's turned on
+func (m *Model) setTVShows(shows []MediaItem) {
+	m.tvShows = shows
+	sorted := shows
+	if m.config.UnwatchedShowsFirst {
+		sorted = append([]MediaItem(nil), shows...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return newEpisodes(sorted[i]) > 0 && newEpisodes(sorted[j]) == 0
+		})
+		m.tvShowsList.Title = "TV Shows (unwatched first)"
+	} else {
+		m.tvShowsList.Title = "TV Shows"
+	}
+	m.tvShowsList.SetItems(convertToListItems(sorted))
+}
+
+// toggleShowSort switches between listing shows with unwatched episodes
+// first and the server's order, keeping the highlighted show, and saves the
+// choice
+func (m Model) toggleShowSort() Model {
+	m.config.UnwatchedShowsFirst = !m.config.UnwatchedShowsFirst
+	selected, _ := m.tvShowsList.SelectedItem().(MediaItem)
+	m.setTVShows(m.tvShows)
+	selectItem(&m.tvShowsList, selected.ID)
+	if err := saveConfig(m.config); err != nil {
+		m.status = fmt.Sprintf("Failed to save sort preference: %v", err)
+	}
+	return m
+}
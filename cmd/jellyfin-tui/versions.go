@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// SourceItem is a version of a video shown in the versions view
+type SourceItem struct {
+	jellyfin.MediaSource
+}
+
+func (s SourceItem) Title() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.ID
+}
+
+// Description shows what tells versions apart, e.g. "2160p · hevc · 45.2 Mbps · mkv"
+func (s SourceItem) Description() string {
+	var parts []string
+	if video, ok := s.VideoStream(); ok {
+		if video.Height > 0 {
+			parts = append(parts, fmt.Sprintf("%dp", video.Height))
+		}
+		if video.Codec != "" {
+			parts = append(parts, video.Codec)
+		}
+	}
+	if s.Bitrate > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f Mbps", float64(s.Bitrate)/1000000))
+	}
+	if s.Container != "" {
+		parts = append(parts, s.Container)
+	}
+	if len(parts) == 0 {
+		return "version"
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (s SourceItem) FilterValue() string { return s.Name }
+
+// mediaSourcesMsg carries the versions of an item that was selected to play,
+// and the item with its further parts if it's split across files
+type mediaSourcesMsg struct {
+	item     MediaItem
+	sources  []jellyfin.MediaSource
+	err      error
+	partsErr error
+}
+
+// preferredVersion returns the configured version preference
+func (c Config) preferredVersion() string {
+	if c.PreferredVersion == "lowest" {
+		return "lowest"
+	}
+	return "highest"
+}
+
+// isVideo reports whether an item is a video that may have several versions
+func isVideo(item MediaItem) bool {
+	switch item.ItemType {
+	case "Movie", "Episode", "Video", "MusicVideo":
+		return true
+	}
+	return false
+}
+
+// startPlayback plays an item, first asking which version to play when a
+// video has more than one. With a bandwidth limit configured, videos ask
+// whether to keep to it or play the original. Listing the versions is
+// cancelled, along with the view's other fetches, on leaving the view.
+func (m Model) startPlayback(item MediaItem) (Model, tea.Cmd) {
+	if !isVideo(item) {
+		return m, m.playItem(item)
+	}
+	ctx := m.fetchCtx
+	if ctx == nil || ctx.Err() != nil {
+		ctx = m.viewContext()
+	}
+	if limit := m.config.describeStreamLimits(); limit != "" {
+		limited := item
+		limited.Limited = true
+		return m.askChoice(fmt.Sprintf("Limit %s to %s? n plays the original", item.Title(), limit),
+			fetchMediaSources(ctx, m.client, limited),
+			fetchMediaSources(ctx, m.client, item))
+	}
+	return m, fetchMediaSources(ctx, m.client, item)
+}
+
+// chooseSource plays an item with a single version, or opens the versions
+// view with the preferred version highlighted
+func (m Model) chooseSource(msg mediaSourcesMsg) (Model, tea.Cmd) {
+	var problems []string
+	if msg.err != nil {
+		problems = append(problems, fmt.Sprintf("Couldn't list versions (%v); playing the default", msg.err))
+	}
+	if msg.partsErr != nil {
+		problems = append(problems, fmt.Sprintf("Couldn't look up further parts of %s (%v); playing its first file", msg.item.Title(), msg.partsErr))
+	}
+	if len(problems) > 0 {
+		m.status = strings.Join(problems, ". ")
+	}
+	if len(msg.sources) <= 1 {
+		return m, m.playItem(msg.item)
+	}
+
+	items := make([]list.Item, len(msg.sources))
+	for i, source := range msg.sources {
+		items[i] = SourceItem{source}
+	}
+	m.versionsList.SetItems(items)
+	m.versionsList.Select(preferredSource(msg.sources, m.config.preferredVersion()))
+	m.versionsList.Title = fmt.Sprintf("Versions of %s", msg.item.Title())
+
+	m.versionItem = msg.item
+	m.returnTo["versions"] = m.currentView
+	m.currentView = "versions"
+	return m, nil
+}
+
+// preferredSource returns the index of the highest or lowest bitrate source
+func preferredSource(sources []jellyfin.MediaSource, preference string) int {
+	best := 0
+	for i, source := range sources {
+		if preference == "lowest" && source.Bitrate < sources[best].Bitrate ||
+			preference != "lowest" && source.Bitrate > sources[best].Bitrate {
+			best = i
+		}
+	}
+	return best
+}
+
+// updateVersions handles input in the versions view: enter plays the
+// highlighted version, s searches for subtitles to play it with
+func (m Model) updateVersions(msg tea.Msg) (Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "s" &&
+		m.versionsList.FilterState() != list.Filtering {
+		return m.openSubtitles(m.versionItem)
+	}
+
+	var cmd tea.Cmd
+	m.versionsList, cmd = m.versionsList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" &&
+		m.versionsList.FilterState() != list.Filtering {
+		if selected, ok := m.versionsList.SelectedItem().(SourceItem); ok {
+			item := m.versionItem
+			item.SourceID = selected.ID
+			m.currentView = m.returnTo["versions"]
+			return m, m.playItem(item)
+		}
+	}
+
+	return m, cmd
+}
+
+// Command to fetch the versions of an item, and any further parts it's split
+// into, before playing it. The two are looked up at once, so playback waits
+// for only the slower.
+func fetchMediaSources(ctx context.Context, client *jellyfin.Client, item MediaItem) tea.Cmd {
+	return func() tea.Msg {
+		var parts []jellyfin.MediaItem
+		var partsErr error
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			parts, partsErr = client.GetAdditionalParts(ctx, item.ID)
+		}()
+
+		sources, err := client.GetMediaSources(ctx, item.ID)
+		<-done
+		if errors.Is(err, context.Canceled) || errors.Is(partsErr, context.Canceled) {
+			return nil
+		}
+		item.Parts = convertItems(client, parts)
+		return mediaSourcesMsg{item: item, sources: sources, err: err, partsErr: partsErr}
+	}
+}
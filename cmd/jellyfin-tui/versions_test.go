@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestChooseSourceReportsBothFailures(t *testing.T) {
+	m := testModel(t, "http://jellyfin")
+	m.printPlay = true
+	msg := mediaSourcesMsg{
+		item:     MediaItem{ID: "item", ItemTitle: "Kill Bill", StreamURL: "http://jellyfin/Videos/item/stream"},
+		err:      errors.New("versions timed out"),
+		partsErr: errors.New("parts timed out"),
+	}
+
+	m, _ = m.chooseSource(msg)
+	for _, want := range []string{"versions timed out", "parts timed out"} {
+		if !strings.Contains(m.status, want) {
+			t.Errorf("status %q doesn't mention %q", m.status, want)
+		}
+	}
+}
+
+func TestSubtitlesFromVersions(t *testing.T) {
+	m := testModel(t, "http://jellyfin")
+	m.versionItem = MediaItem{ID: "item", ItemTitle: "Kill Bill", ItemType: "Movie"}
+	m.returnTo["versions"] = "movies"
+	m.currentView = "versions"
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(Model)
+	if m.currentView != "subtitles" || m.subtitleItem.ID != "item" {
+		t.Fatalf("s in the versions view opened %q for %q, want subtitles for item", m.currentView, m.subtitleItem.ID)
+	}
+
+	updated, _ = m.Update(subtitleDownloadedMsg("subtitle"))
+	if view := updated.(Model).currentView; view != "versions" {
+		t.Errorf("after the download the view is %q, want versions", view)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fabean/jellyfin-tui/jellyfin"
+)
+
+// playedMsg reports the result of marking an item watched or unwatched
+type playedMsg struct {
+	item   MediaItem
+	played bool
+	err    error
+}
+
+// unwatchCountMsg carries how many watched items marking a series, season
+// or folder unwatched would affect
+type unwatchCountMsg struct {
+	item  MediaItem
+	count int
+	err   error
+}
+
+// confirmUnwatch returns when marking unwatched is confirmed: "bulk" for
+// series, seasons and folders only, "always", or "never"
+func (c Config) confirmUnwatch() string {
+	switch c.ConfirmUnwatch {
+	case "always", "never":
+		return c.ConfirmUnwatch
+	}
+	return "bulk"
+}
+
+// toggleWatched marks an item watched, or unwatched if it's been watched.
+// Unwatching a series, season or folder loses the progress of everything in
+// it, so it's confirmed first unless that's turned off.
+func (m Model) toggleWatched(item MediaItem) (Model, tea.Cmd) {
+	if !item.UserData.Played {
+		return m, setPlayed(m.client, item, true)
+	}
+
+	switch {
+	case m.config.confirmUnwatch() == "never":
+		return m, setPlayed(m.client, item, false)
+	case item.IsFolder:
+		m.status = fmt.Sprintf("Counting watched items in %s...", item.Title())
+		return m, countWatched(m.client, item)
+	case m.config.confirmUnwatch() == "always":
+		return m.askConfirm(fmt.Sprintf("Mark %s unwatched?", item.Title()), setPlayed(m.client, item, false))
+	}
+	return m, setPlayed(m.client, item, false)
+}
+
+// confirmBulkUnwatch asks before unwatching everything in a series, season
+// or folder, saying how much will be affected
+func (m Model) confirmBulkUnwatch(msg unwatchCountMsg) (Model, tea.Cmd) {
+	action := setPlayed(m.client, msg.item, false)
+	switch {
+	case msg.err != nil:
+		return m.askConfirm(fmt.Sprintf("Mark everything in %s unwatched?", msg.item.Title()), action)
+	case msg.count == 0:
+		m.status = fmt.Sprintf("Nothing in %s is watched", msg.item.Title())
+		return m, nil
+	case msg.count == 1:
+		return m.askConfirm(fmt.Sprintf("Mark 1 watched item in %s unwatched?", msg.item.Title()), action)
+	}
+	return m.askConfirm(fmt.Sprintf("Mark %d watched items in %s unwatched?", msg.count, msg.item.Title()), action)
+}
+
+// updatePlayed reports a change of watched state and reloads the view to
+// show it
+func (m Model) updatePlayed(msg playedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.status = fmt.Sprintf("Couldn't update watched state of %s: %v", msg.item.Title(), msg.err)
+		return m, nil
+	}
+
+	state := "watched"
+	if !msg.played {
+		state = "unwatched"
+	}
+	m, cmd := m.refreshView()
+	m.status = fmt.Sprintf("Marked %s %s", msg.item.Title(), state)
+	return m, cmd
+}
+
+// Command to mark an item watched or unwatched
+func setPlayed(client *jellyfin.Client, item MediaItem, played bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		defer cancel()
+
+		err := client.SetPlayed(ctx, item.ID, played)
+		return playedMsg{item: item, played: played, err: err}
+	}
+}
+
+// Command to count the watched items in a series, season or folder
+func countWatched(client *jellyfin.Client, item MediaItem) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		defer cancel()
+
+		count, err := client.CountPlayed(ctx, item.ID)
+		return unwatchCountMsg{item: item, count: count, err: err}
+	}
+}
@@ -1,11 +1,23 @@
 package jellyfin
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // Client represents a Jellyfin API client
@@ -13,17 +25,73 @@ type Client struct {
 	ServerURL string
 	APIKey    string
 	HTTPClient *http.Client
+
+	// Headers are sent with every request, such as those an authenticating
+	// proxy in front of the server needs. They can't replace the
+	// Authorization and Accept headers the client sets.
+	Headers http.Header
+
+	// UserID is the user for per-user data; looked up on first use if empty
+	UserID string
+	userMu sync.Mutex
+
+	// The last failed lookup of the user, returned again until
+	// userRetryInterval has passed rather than asking the server every time
+	userErr   error
+	userErrAt time.Time
+
+	// requests coalesces concurrent fetches of the same endpoint
+	requests singleflight.Group
+
+	// slots holds a token for each request in flight, see SetMaxConcurrency;
+	// nil doesn't limit them
+	slots chan struct{}
+
+	// Identify this device to the server; see authorization
+	deviceName string
+	deviceID   string
 }
 
+// ClientVersion is reported to the server along with the client name
+const ClientVersion = "0.1.0"
+
+// clientName is how the app appears in the server's list of sessions
+const clientName = "jellyfin-tui"
+
 // NewClient creates a new Jellyfin client
 func NewClient(serverURL, apiKey string) *Client {
+	deviceName, err := os.Hostname()
+	if err != nil {
+		deviceName = clientName
+	}
+
 	return &Client{
 		ServerURL: serverURL,
 		APIKey:    apiKey,
 		HTTPClient: &http.Client{},
+
+		deviceName: deviceName,
+		deviceID:   deviceID(deviceName),
+	}
+}
+
+// SetMaxConcurrency limits how many requests the client sends at once,
+// whichever feature sends them; the rest wait for one to finish. Zero or
+// less removes the limit. It must be called before the client is shared.
+func (c *Client) SetMaxConcurrency(n int) {
+	c.slots = nil
+	if n > 0 {
+		c.slots = make(chan struct{}, n)
 	}
 }
 
+// deviceID derives a device ID that stays the same across runs on a host,
+// so the server doesn't list a new device every time the app starts
+func deviceID(deviceName string) string {
+	sum := sha256.Sum256([]byte(clientName + "/" + deviceName))
+	return hex.EncodeToString(sum[:16])
+}
+
 // MediaItem represents a movie, TV show, or episode
 type MediaItem struct {
 	ID           string            `json:"Id"`
@@ -32,30 +100,287 @@ type MediaItem struct {
 	MediaType    string            `json:"MediaType"`
 	ImageTags    map[string]string `json:"ImageTags"`
 	IndexNumber  int               `json:"IndexNumber"`
+	IsFolder     bool              `json:"IsFolder"`
+
+	// Video resolution, when requested and known
+	Width  int `json:"Width"`
+	Height int `json:"Height"`
+
+	// Episode metadata
+	SeriesName        string `json:"SeriesName"`
+	ParentIndexNumber int    `json:"ParentIndexNumber"` // Season number
+	SeasonID          string `json:"SeasonId"`
+	SeasonName        string `json:"SeasonName"`
+
+	// Music metadata. Album artists are who an album is filed under; a
+	// track's artists may differ, e.g. on compilations.
+	Album          string       `json:"Album"`
+	AlbumID        string       `json:"AlbumId"`
+	AlbumArtist    string       `json:"AlbumArtist"`
+	AlbumArtists   []NameIDPair `json:"AlbumArtists"`
+	Artists        []string     `json:"Artists"`
+	AlbumImageTag  string       `json:"AlbumPrimaryImageTag"` // Tracks: the album's cover
+	ArtistItems    []NameIDPair `json:"ArtistItems"`
+	RunTimeTicks   int64        `json:"RunTimeTicks"`
+	ProductionYear int          `json:"ProductionYear"`
+	ChildCount     int          `json:"ChildCount"`
+
+	UserData UserData `json:"UserData"`
+}
+
+// NameIDPair references another item, such as an artist, by name and ID
+type NameIDPair struct {
+	ID   string `json:"Id"`
+	Name string `json:"Name"`
+}
+
+// Optional fields requested by each list. Core properties (Name, Type,
+// IndexNumber, RunTimeTicks, ProductionYear, UserData...) always come back;
+// lists ask only for the extras they render rather than full item objects.
+var (
+	fieldsNone   []string
+	fieldsAlbums = []string{"ChildCount"}      // Track count
+	fieldsVideos = []string{"Width", "Height"} // Resolution badge
+)
+
+// listParams limits a list fetch to the given optional fields and to the
+// primary image tag, leaving out backdrop, logo and thumb tags and their
+// blurhashes, which make up much of a large library's response. Without
+// fields, the parameter is left out rather than sent empty.
+func listParams(fields []string) string {
+	params := "&EnableImageTypes=Primary&ImageTypeLimit=1"
+	if len(fields) > 0 {
+		params = "&Fields=" + strings.Join(fields, ",") + params
+	}
+	return params
 }
 
 // GetMovies fetches movies from the Jellyfin server
-func (c *Client) GetMovies() ([]MediaItem, error) {
+func (c *Client) GetMovies(ctx context.Context) ([]MediaItem, error) {
 	endpoint := fmt.Sprintf("%s/Items?IncludeItemTypes=Movie&Recursive=true&api_key=%s", 
 		c.ServerURL, c.APIKey)
 	
-	return c.fetchItems(endpoint)
+	return c.fetchItems(ctx, endpoint+listParams(fieldsVideos))
+}
+
+// GetAllMedia fetches movies and TV shows together, most recently added first
+func (c *Client) GetAllMedia(ctx context.Context) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/Items?IncludeItemTypes=Movie,Series&Recursive=true&SortBy=DateCreated,SortName&SortOrder=Descending&api_key=%s",
+		c.ServerURL, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsVideos))
 }
 
 // GetTVShows fetches TV shows from the Jellyfin server
-func (c *Client) GetTVShows() ([]MediaItem, error) {
-	endpoint := fmt.Sprintf("%s/Items?IncludeItemTypes=Series&Recursive=true&api_key=%s", 
+func (c *Client) GetTVShows(ctx context.Context) ([]MediaItem, error) {
+	// User data carries each show's count of unwatched episodes
+	endpoint := fmt.Sprintf("%s/Items?IncludeItemTypes=Series&Recursive=true&EnableUserData=true&api_key=%s", 
 		c.ServerURL, c.APIKey)
 	
-	return c.fetchItems(endpoint)
+	return c.fetchItems(ctx, endpoint+listParams(fieldsNone))
 }
 
-// Search searches for media items
-func (c *Client) Search(query string) ([]MediaItem, error) {
-	endpoint := fmt.Sprintf("%s/Items?SearchTerm=%s&Recursive=true&api_key=%s", 
-		c.ServerURL, url.QueryEscape(query), c.APIKey)
-	
-	return c.fetchItems(endpoint)
+// GetMusicAlbums fetches music albums from the Jellyfin server
+func (c *Client) GetMusicAlbums(ctx context.Context) ([]MediaItem, error) {
+	return c.getMusicAlbums(ctx, "")
+}
+
+// GetAlbumsByGenre fetches the music albums in a genre
+func (c *Client) GetAlbumsByGenre(ctx context.Context, genreID string) ([]MediaItem, error) {
+	return c.getMusicAlbums(ctx, "&GenreIds="+url.QueryEscape(genreID))
+}
+
+// GetAlbumsByArtist fetches the music albums filed under an album artist
+func (c *Client) GetAlbumsByArtist(ctx context.Context, artistID string) ([]MediaItem, error) {
+	return c.getMusicAlbums(ctx, "&AlbumArtistIds="+url.QueryEscape(artistID))
+}
+
+// getMusicAlbums fetches music albums matching an optional filter
+func (c *Client) getMusicAlbums(ctx context.Context, filter string) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/Items?IncludeItemTypes=MusicAlbum&Recursive=true&SortBy=AlbumArtist,SortName&api_key=%s",
+		c.ServerURL, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+filter+listParams(fieldsAlbums))
+}
+
+// GetMusicGenres fetches the genres of the server's music
+func (c *Client) GetMusicGenres(ctx context.Context) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/MusicGenres?SortBy=SortName&api_key=%s", c.ServerURL, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsNone))
+}
+
+// GetAlbumArtists fetches the artists albums are filed under, leaving out
+// artists who only appear on other artists' tracks
+func (c *Client) GetAlbumArtists(ctx context.Context) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/Artists/AlbumArtists?SortBy=SortName&api_key=%s", c.ServerURL, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsNone))
+}
+
+// GetAlbumTracks fetches the tracks of a music album in disc and track order
+func (c *Client) GetAlbumTracks(ctx context.Context, albumID string) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/Items?ParentId=%s&IncludeItemTypes=Audio&SortBy=ParentIndexNumber,IndexNumber,SortName&api_key=%s",
+		c.ServerURL, albumID, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsNone))
+}
+
+// GetLibraries fetches the user's libraries as the web client shows them:
+// in the order set in their display preferences, leaving out those they've
+// hidden. If the server can't list the user's views, all of its media
+// folders are fetched instead, in its own order.
+func (c *Client) GetLibraries(ctx context.Context) ([]MediaItem, error) {
+	views, err := c.getUserViews(ctx)
+	if err == nil || isContextError(err) {
+		return views, err
+	}
+
+	endpoint := fmt.Sprintf("%s/Library/MediaFolders?api_key=%s", c.ServerURL, c.APIKey)
+	return c.fetchItems(ctx, endpoint)
+}
+
+// getUserViews fetches the user's libraries from /Users/{id}/Views, which
+// the server orders and filters by the user's preferences
+func (c *Client) getUserViews(ctx context.Context) ([]MediaItem, error) {
+	userID, err := c.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/Users/%s/Views?api_key=%s", c.ServerURL, userID, c.APIKey)
+	return c.fetchItems(ctx, endpoint)
+}
+
+// GetChildren fetches the direct children of a folder, folders first: IsFolder
+// sorts descending, true before false, then names ascending
+func (c *Client) GetChildren(ctx context.Context, parentID string) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/Items?ParentId=%s&SortBy=IsFolder,SortName&SortOrder=Descending,Ascending&api_key=%s",
+		c.ServerURL, parentID, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsVideos))
+}
+
+// GetLatest fetches recently added items, newest first. With hideWatched
+// the server is asked to leave out played items; they're also dropped here
+// in case the server ignores the filter.
+func (c *Client) GetLatest(ctx context.Context, limit int, hideWatched bool) ([]MediaItem, error) {
+	userID, err := c.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/Users/%s/Items/Latest?Limit=%d&api_key=%s",
+		c.ServerURL, userID, limit, c.APIKey)
+	if hideWatched {
+		endpoint += "&IsPlayed=false"
+	}
+
+	items, err := c.fetchItems(ctx, endpoint+listParams(fieldsVideos))
+	if err != nil || !hideWatched {
+		return items, err
+	}
+
+	unwatched := items[:0]
+	for _, item := range items {
+		if !item.UserData.Played {
+			unwatched = append(unwatched, item)
+		}
+	}
+	return unwatched, nil
+}
+
+// GetSeasons fetches the seasons of a series
+func (c *Client) GetSeasons(ctx context.Context, seriesID string) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/Shows/%s/Seasons?api_key=%s", c.ServerURL, seriesID, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsNone))
+}
+
+// GetEpisodes fetches the episodes of a season
+func (c *Client) GetEpisodes(ctx context.Context, seasonID string) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/Items?ParentId=%s&SortBy=SortName&api_key=%s", c.ServerURL, seasonID, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsVideos))
+}
+
+// GetAllEpisodes fetches every episode of a series, in season and episode order
+func (c *Client) GetAllEpisodes(ctx context.Context, seriesID string) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/Shows/%s/Episodes?api_key=%s", c.ServerURL, seriesID, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsVideos))
+}
+
+// GetResumeItems fetches partially watched videos, most recent first
+func (c *Client) GetResumeItems(ctx context.Context, limit int) ([]MediaItem, error) {
+	userID, err := c.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/Users/%s/Items/Resume?Limit=%d&MediaTypes=Video&api_key=%s",
+		c.ServerURL, userID, limit, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsVideos))
+}
+
+// GetRecentlyPlayed fetches the videos, songs and audiobooks the user
+// played most recently, latest first. Items never played are left out.
+func (c *Client) GetRecentlyPlayed(ctx context.Context, limit int) ([]MediaItem, error) {
+	userID, err := c.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/Users/%s/Items?IncludeItemTypes=Movie,Episode,Audio,AudioBook&Recursive=true&SortBy=DatePlayed&SortOrder=Descending&Limit=%d&api_key=%s",
+		c.ServerURL, userID, limit, c.APIKey)
+
+	items, err := c.fetchItems(ctx, endpoint+listParams(fieldsVideos))
+	if err != nil {
+		return nil, err
+	}
+
+	// Items never played sort last, and fill the page if few have been
+	played := items[:0]
+	for _, item := range items {
+		if _, ok := item.UserData.LastPlayed(); ok {
+			played = append(played, item)
+		}
+	}
+	return played, nil
+}
+
+// GetNextUp fetches the next unwatched episode of each series in progress
+func (c *Client) GetNextUp(ctx context.Context, limit int) ([]MediaItem, error) {
+	userID, err := c.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/Shows/NextUp?UserId=%s&Limit=%d&api_key=%s",
+		c.ServerURL, userID, limit, c.APIKey)
+
+	return c.fetchItems(ctx, endpoint+listParams(fieldsVideos))
+}
+
+// ItemsPage is one page of a listing too long to fetch at once
+type ItemsPage struct {
+	Items      []MediaItem `json:"Items"`
+	StartIndex int         `json:"StartIndex"`
+	TotalCount int         `json:"TotalRecordCount"` // Across all pages
+}
+
+// Search searches for media items, returning up to limit results starting
+// at startIndex along with the total number of matches
+func (c *Client) Search(ctx context.Context, query string, startIndex, limit int) (ItemsPage, error) {
+	endpoint := fmt.Sprintf("%s/Items?SearchTerm=%s&Recursive=true&StartIndex=%d&Limit=%d&api_key=%s",
+		c.ServerURL, url.QueryEscape(query), startIndex, limit, c.APIKey)
+
+	var page ItemsPage
+	if err := c.getJSON(ctx, c.withUser(ctx, endpoint)+listParams(fieldsVideos), &page); err != nil {
+		return ItemsPage{}, err
+	}
+	return page, nil
 }
 
 // GetStreamURL returns the streaming URL for a media item
@@ -63,23 +388,228 @@ func (c *Client) GetStreamURL(itemID string) string {
 	return fmt.Sprintf("%s/Videos/%s/stream?api_key=%s", c.ServerURL, itemID, c.APIKey)
 }
 
-// Helper function to fetch items from an endpoint
-func (c *Client) fetchItems(endpoint string) ([]MediaItem, error) {
-	resp, err := c.HTTPClient.Get(endpoint)
+// GetAudioStreamURL returns the streaming URL for an audio item
+func (c *Client) GetAudioStreamURL(itemID string) string {
+	return fmt.Sprintf("%s/Audio/%s/stream?static=true&api_key=%s", c.ServerURL, itemID, c.APIKey)
+}
+
+// GetWebURL returns the item's details page in the Jellyfin web client
+func (c *Client) GetWebURL(itemID string) string {
+	return fmt.Sprintf("%s/web/index.html#!/details?id=%s", c.ServerURL, itemID)
+}
+
+// GetImageURL returns the URL of an item's primary image (poster or album art).
+// tag is the image's entry in ImageTags; it changes when the artwork does,
+// giving the new artwork a new URL. It may be empty if unknown.
+func (c *Client) GetImageURL(itemID, tag string) string {
+	imageURL := fmt.Sprintf("%s/Items/%s/Images/Primary?api_key=%s", c.ServerURL, itemID, c.APIKey)
+	if tag != "" {
+		imageURL += "&tag=" + url.QueryEscape(tag)
+	}
+	return imageURL
+}
+
+// GetImage fetches an item's primary image as a JPEG, scaled down by the
+// server to fit within maxWidth x maxHeight pixels
+func (c *Client) GetImage(ctx context.Context, itemID, tag string, maxWidth, maxHeight int) ([]byte, error) {
+	endpoint := fmt.Sprintf("%s&maxWidth=%d&maxHeight=%d&format=Jpg", c.GetImageURL(itemID, tag), maxWidth, maxHeight)
+
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/jpeg")
+	return c.send(req)
+}
+
+// StatusError is returned when the server answers with an unexpected status
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API request failed with status: %s", e.Status)
+}
+
+// ContentTypeError is returned when the server answers a request for JSON
+// with something else, typically a proxy's login or error page
+type ContentTypeError struct {
+	ContentType string
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("expected JSON but the server sent %s; check that the server URL points at Jellyfin rather than a proxy or login page", e.ContentType)
+}
+
+// newRequest builds a request to the server. Every request the client makes
+// is built here, so it's cancelled along with ctx and identifies this device.
+func (c *Client) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Authorization", c.authorization())
+	req.Header.Set("Accept", "application/json") // Some proxies answer with XML or HTML otherwise
+	return req, nil
+}
+
+// authorization returns the Authorization header that registers the app
+// as a session on the server, so its playback shows up on the dashboard
+func (c *Client) authorization() string {
+	return fmt.Sprintf(`MediaBrowser Client="%s", Device="%s", DeviceId="%s", Version="%s", Token="%s"`,
+		clientName, c.deviceName, c.deviceID, ClientVersion, c.APIKey)
+}
+
+// do sends a request and returns the response body, failing with a
+// StatusError on anything other than a 2xx status
+func (c *Client) do(ctx context.Context, method, endpoint string) ([]byte, error) {
+	req, err := c.newRequest(ctx, method, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.send(req)
+}
+
+// postJSON sends v as the JSON body of a POST request
+func (c *Client) postJSON(ctx context.Context, endpoint string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.send(req)
+	return err
+}
+
+// send sends a built request, see do, once there's a free slot. A response
+// to a request accepting JSON must be JSON, or empty.
+func (c *Client) send(req *http.Request) ([]byte, error) {
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+			defer func() { <-c.slots }()
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status: %s", resp.Status)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
-	
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
+	contentType := resp.Header.Get("Content-Type")
+	if len(body) > 0 && req.Header.Get("Accept") == "application/json" && !isJSON(contentType) {
+		return nil, &ContentTypeError{ContentType: contentType}
+	}
+	return body, nil
+}
+
+// isJSON reports whether a Content-Type is JSON. A missing one is given the
+// benefit of the doubt.
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// getJSON fetches an endpoint and decodes the JSON response into v
+func (c *Client) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	body, err := c.do(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// Helper function to fetch items from an endpoint. Concurrent calls for the
+// same endpoint share a single request and response.
+func (c *Client) fetchItems(ctx context.Context, endpoint string) ([]MediaItem, error) {
+	endpoint = c.withUser(ctx, endpoint)
+
+	for attempt := 0; ; attempt++ {
+		ch := c.requests.DoChan(endpoint, func() (interface{}, error) {
+			return c.doFetchItems(ctx, endpoint)
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case res := <-ch:
+			if res.Err != nil {
+				// The shared request ran under another caller's context; if that
+				// caller gave up, try again under ours
+				if res.Shared && attempt == 0 && ctx.Err() == nil && isContextError(res.Err) {
+					continue
+				}
+				return nil, res.Err
+			}
+
+			// Every caller gets its own copy of the shared result
+			return append([]MediaItem(nil), res.Val.([]MediaItem)...), nil
+		}
+	}
+}
+
+// withUser adds the user to an items endpoint so UserData (watched state,
+// favorites) is filled in; if the user can't be looked up the items are
+// fetched without it
+func (c *Client) withUser(ctx context.Context, endpoint string) string {
+	if !strings.Contains(endpoint, "UserId=") && !strings.Contains(endpoint, "/Users/") {
+		if userID, err := c.userID(ctx); err == nil {
+			endpoint += "&UserId=" + userID
+		}
+	}
+	return endpoint
+}
+
+// isContextError reports whether err came from a cancelled or expired context
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// doFetchItems performs the request behind fetchItems
+func (c *Client) doFetchItems(ctx context.Context, endpoint string) ([]MediaItem, error) {
+	body, err := c.do(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return nil, err
+	}
 	
+	// Some endpoints, such as Items/Latest, return a bare array
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []MediaItem
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+
 	var response struct {
 		Items []MediaItem `json:"Items"`
 	}
@@ -92,6 +622,6 @@ func (c *Client) fetchItems(endpoint string) ([]MediaItem, error) {
 }
 
 // FetchItems fetches items from a custom endpoint
-func (c *Client) FetchItems(endpoint string) ([]MediaItem, error) {
-	return c.fetchItems(endpoint)
+func (c *Client) FetchItems(ctx context.Context, endpoint string) ([]MediaItem, error) {
+	return c.fetchItems(ctx, endpoint)
 } 
\ No newline at end of file
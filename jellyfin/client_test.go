@@ -0,0 +1,228 @@
+package jellyfin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExtraHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "key")
+	c.Headers = http.Header{}
+	c.Headers.Set("CF-Access-Client-Id", "id")
+	c.Headers.Add("X-Forwarded-Groups", "a")
+	c.Headers.Add("X-Forwarded-Groups", "b")
+	c.Headers.Set("Authorization", "Bearer proxy")
+	c.Headers.Set("Accept", "text/html")
+
+	if _, err := c.do(context.Background(), http.MethodGet, server.URL+"/System/Info"); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := got.Get("CF-Access-Client-Id"); v != "id" {
+		t.Errorf("CF-Access-Client-Id = %q, want %q", v, "id")
+	}
+	if v := got.Values("X-Forwarded-Groups"); len(v) != 2 || v[0] != "a" || v[1] != "b" {
+		t.Errorf("X-Forwarded-Groups = %q, want [a b]", v)
+	}
+	if v := got.Values("Authorization"); len(v) != 1 || v[0] != c.authorization() {
+		t.Errorf("Authorization = %q, want only the client's", v)
+	}
+	if v := got.Values("Accept"); len(v) != 1 || v[0] != "application/json" {
+		t.Errorf("Accept = %q, want only application/json", v)
+	}
+}
+
+func TestGetImageURL(t *testing.T) {
+	c := NewClient("http://jellyfin", "key")
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"", "http://jellyfin/Items/item/Images/Primary?api_key=key"},
+		{"abc123", "http://jellyfin/Items/item/Images/Primary?api_key=key&tag=abc123"},
+		{"a&b c", "http://jellyfin/Items/item/Images/Primary?api_key=key&tag=a%26b+c"},
+	}
+	for _, tt := range tests {
+		if got := c.GetImageURL("item", tt.tag); got != tt.want {
+			t.Errorf("GetImageURL(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A login page in front of the server, answering instead of it
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<html><body>Sign in</body></html>"))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "key")
+	_, err := c.GetSystemInfo(context.Background())
+
+	var contentTypeErr *ContentTypeError
+	if !errors.As(err, &contentTypeErr) {
+		t.Fatalf("got error %v, want a ContentTypeError", err)
+	}
+	if contentTypeErr.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("ContentType = %q, want the one the server sent", contentTypeErr.ContentType)
+	}
+}
+
+func TestListParams(t *testing.T) {
+	tests := []struct {
+		fields []string
+		want   string
+	}{
+		{fieldsNone, "&EnableImageTypes=Primary&ImageTypeLimit=1"},
+		{fieldsAlbums, "&Fields=ChildCount&EnableImageTypes=Primary&ImageTypeLimit=1"},
+		{fieldsVideos, "&Fields=Width,Height&EnableImageTypes=Primary&ImageTypeLimit=1"},
+	}
+	for _, tt := range tests {
+		if got := listParams(tt.fields); got != tt.want {
+			t.Errorf("listParams(%q) = %q, want %q", tt.fields, got, tt.want)
+		}
+	}
+}
+
+func TestGetChildrenFoldersFirst(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Items": []}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "key")
+	c.UserID = "user"
+	if _, err := c.GetChildren(context.Background(), "folder"); err != nil {
+		t.Fatal(err)
+	}
+	if by, order := query.Get("SortBy"), query.Get("SortOrder"); by != "IsFolder,SortName" || order != "Descending,Ascending" {
+		t.Errorf("SortBy=%s&SortOrder=%s, want folders (IsFolder descending) first, then names ascending", by, order)
+	}
+}
+
+func TestCancelledFetch(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := NewClient(server.URL, "key")
+	c.UserID = "user"
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	if _, err := c.GetMovies(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestSharedFetchOutlivesCancelledCaller(t *testing.T) {
+	var requests atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// The first caller's request, held until it gives up
+			close(started)
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Items": [{"Id": "movie", "Name": "Movie"}]}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := NewClient(server.URL, "key")
+	c.UserID = "user"
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := c.GetMovies(first)
+		firstErr <- err
+	}()
+	<-started
+
+	type result struct {
+		items []MediaItem
+		err   error
+	}
+	second := make(chan result, 1)
+	go func() {
+		items, err := c.GetMovies(context.Background())
+		second <- result{items, err}
+	}()
+	// Give the second caller time to join the first one's request
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller got error %v, want context.Canceled", err)
+	}
+	got := <-second
+	if got.err != nil {
+		t.Fatalf("the caller still waiting got error %v, want the movies", got.err)
+	}
+	if len(got.items) != 1 || got.items[0].ID != "movie" {
+		t.Errorf("the caller still waiting got %+v, want the one movie", got.items)
+	}
+}
+
+func TestDecodeMinimalItem(t *testing.T) {
+	// Only the properties every item has; UserData, RunTimeTicks, ChildCount
+	// and ImageTags are left out, as when a list doesn't ask for them
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Items": [{"Id": "movie", "Name": "Movie", "Type": "Movie", "IsFolder": false}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, "key")
+	c.UserID = "user"
+	items, err := c.GetMovies(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	item := items[0]
+	if item.ID != "movie" || item.Name != "Movie" || item.Type != "Movie" {
+		t.Errorf("got %+v, want the movie's Id, Name and Type", item)
+	}
+	if item.UserData != (UserData{}) || item.RunTimeTicks != 0 || item.ChildCount != 0 {
+		t.Errorf("got %+v, want zero values for the missing fields", item)
+	}
+	if tag := item.ImageTags["Primary"]; tag != "" {
+		t.Errorf("Primary image tag = %q, want none", tag)
+	}
+}
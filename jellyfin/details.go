@@ -0,0 +1,42 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+)
+
+// ItemDetails is the full metadata of an item, beyond what lists fetch
+type ItemDetails struct {
+	MediaItem
+	Overview            string       `json:"Overview"`
+	Taglines            []string     `json:"Taglines"`
+	OfficialRating      string       `json:"OfficialRating"`  // e.g. "PG-13"
+	CommunityRating     float64      `json:"CommunityRating"` // Out of 10
+	Genres              []string     `json:"Genres"`
+	People              []Person     `json:"People"`
+	Studios             []NameIDPair `json:"Studios"`
+	ProductionLocations []string     `json:"ProductionLocations"` // Countries the item was made in
+}
+
+// Person is someone who worked on an item
+type Person struct {
+	Name string `json:"Name"`
+	Role string `json:"Role"` // Actors: the character played
+	Type string `json:"Type"` // e.g. "Actor", "Director", "Writer"
+}
+
+// GetItemDetails fetches the full metadata of an item
+func (c *Client) GetItemDetails(ctx context.Context, itemID string) (ItemDetails, error) {
+	userID, err := c.userID(ctx)
+	if err != nil {
+		return ItemDetails{}, err
+	}
+
+	endpoint := fmt.Sprintf("%s/Users/%s/Items/%s?api_key=%s", c.ServerURL, userID, itemID, c.APIKey)
+
+	var details ItemDetails
+	if err := c.getJSON(ctx, endpoint, &details); err != nil {
+		return ItemDetails{}, err
+	}
+	return details, nil
+}
@@ -0,0 +1,35 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// RefreshItemMetadata queues a metadata refresh of an item on the server,
+// filling in missing metadata and images without replacing existing ones.
+// The refresh runs in the background after this returns.
+func (c *Client) RefreshItemMetadata(ctx context.Context, itemID string) error {
+	endpoint := fmt.Sprintf("%s/Items/%s/Refresh?MetadataRefreshMode=FullRefresh&ImageRefreshMode=FullRefresh&ReplaceAllMetadata=false&ReplaceAllImages=false&api_key=%s",
+		c.ServerURL, itemID, c.APIKey)
+
+	_, err := c.do(ctx, http.MethodPost, endpoint)
+	return err
+}
+
+// GetLibraryName returns the name of the library an item is in, the nearest
+// of its ancestors that's a library's root folder, or "" if it has none
+func (c *Client) GetLibraryName(ctx context.Context, itemID string) (string, error) {
+	endpoint := fmt.Sprintf("%s/Items/%s/Ancestors?api_key=%s", c.ServerURL, itemID, c.APIKey)
+
+	var ancestors []MediaItem
+	if err := c.getJSON(ctx, c.withUser(ctx, endpoint), &ancestors); err != nil {
+		return "", err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.Type == "CollectionFolder" {
+			return ancestor.Name, nil
+		}
+	}
+	return "", nil
+}
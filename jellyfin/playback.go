@@ -0,0 +1,156 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// MediaSource is one version of an item, such as a 4K and a 1080p file of
+// the same movie
+type MediaSource struct {
+	ID           string        `json:"Id"`
+	Name         string        `json:"Name"`
+	Container    string        `json:"Container"`
+	Bitrate      int64         `json:"Bitrate"`
+	Size         int64         `json:"Size"`
+	MediaStreams []MediaStream `json:"MediaStreams"`
+}
+
+// MediaStream is a video, audio or subtitle stream within a media source
+type MediaStream struct {
+	Type   string `json:"Type"` // "Video", "Audio" or "Subtitle"
+	Codec  string `json:"Codec"`
+	Width  int    `json:"Width"`
+	Height int    `json:"Height"`
+}
+
+// VideoStream returns the source's first video stream, if it has one
+func (s MediaSource) VideoStream() (MediaStream, bool) {
+	for _, stream := range s.MediaStreams {
+		if stream.Type == "Video" {
+			return stream, true
+		}
+	}
+	return MediaStream{}, false
+}
+
+// playbackInfo is the response of the PlaybackInfo endpoint
+type playbackInfo struct {
+	MediaSources []MediaSource `json:"MediaSources"`
+}
+
+// GetMediaSources fetches the versions an item can be played from
+func (c *Client) GetMediaSources(ctx context.Context, itemID string) ([]MediaSource, error) {
+	endpoint := fmt.Sprintf("%s/Items/%s/PlaybackInfo?api_key=%s", c.ServerURL, itemID, c.APIKey)
+	if userID, err := c.userID(ctx); err == nil {
+		endpoint += "&UserId=" + userID
+	}
+
+	var info playbackInfo
+	if err := c.getJSON(ctx, endpoint, &info); err != nil {
+		return nil, err
+	}
+
+	return info.MediaSources, nil
+}
+
+// GetAdditionalParts fetches the further files of a video split across
+// several, such as a movie in "Part 1" and "Part 2", in order. Videos in a
+// single file have none.
+func (c *Client) GetAdditionalParts(ctx context.Context, itemID string) ([]MediaItem, error) {
+	endpoint := fmt.Sprintf("%s/Videos/%s/AdditionalParts?api_key=%s", c.ServerURL, itemID, c.APIKey)
+	return c.fetchItems(ctx, endpoint)
+}
+
+// Streaming protocols for GetPlayURL
+const (
+	ProtocolDirect = "direct" // The file over plain HTTP, remuxed by the server if needed
+	ProtocolHLS    = "hls"    // Short HLS segments, which seek more smoothly over slow or distant connections
+)
+
+// PlayOptions choose how GetPlayURL streams a video
+type PlayOptions struct {
+	SourceID  string // Version to play; empty plays the default
+	Protocol  string // ProtocolDirect (the default) or ProtocolHLS
+	Container string // Direct streams only: serve the file as-is in this container, e.g. "mkv"
+
+	// Transcode has the server transcode to H.264 and AAC within Limits,
+	// which is always streamed over HLS
+	Transcode bool
+	Limits    StreamLimits
+}
+
+// GetPlayURL returns the URL a player streams a video from
+func (c *Client) GetPlayURL(itemID string, opts PlayOptions) string {
+	switch {
+	case opts.Transcode:
+		return c.GetTranscodeURL(itemID, opts.SourceID, opts.Limits)
+	case opts.Protocol == ProtocolHLS:
+		return c.getHLSURL(itemID, opts.SourceID)
+	}
+
+	params := url.Values{}
+	if opts.Container != "" {
+		// For players that mishandle the remux the plain stream can produce
+		params.Set("static", "true")
+		params.Set("Container", opts.Container)
+	}
+	if opts.SourceID != "" {
+		params.Set("MediaSourceId", opts.SourceID)
+	}
+	params.Set("api_key", c.APIKey)
+	return fmt.Sprintf("%s/Videos/%s/stream?%s", c.ServerURL, itemID, params.Encode())
+}
+
+// getHLSURL returns an HLS stream of a video. Streams in codecs common
+// players support are copied into the segments rather than transcoded.
+func (c *Client) getHLSURL(itemID, sourceID string) string {
+	if sourceID == "" {
+		sourceID = itemID
+	}
+	params := url.Values{}
+	params.Set("MediaSourceId", sourceID)
+	params.Set("DeviceId", c.deviceID)
+	params.Set("VideoCodec", "h264,hevc,vp9,av1")
+	params.Set("AudioCodec", "aac,mp3,ac3,eac3,opus,flac")
+	params.Set("AllowVideoStreamCopy", "true")
+	params.Set("AllowAudioStreamCopy", "true")
+	params.Set("api_key", c.APIKey)
+	return fmt.Sprintf("%s/Videos/%s/master.m3u8?%s", c.ServerURL, itemID, params.Encode())
+}
+
+// StreamLimits caps the quality of a transcoded stream; zero values don't
+// limit
+type StreamLimits struct {
+	MaxBitrate int // Bits per second, video and audio together
+	MaxHeight  int // Pixels, e.g. 720
+}
+
+// transcodeAudioBitrate is the audio's share of a limited stream's bitrate
+const transcodeAudioBitrate = 192000
+
+// GetTranscodeURL returns an HLS stream of a video that the server transcodes
+// to H.264 and AAC within limits, for connections too slow for the original.
+// sourceID picks the version to transcode; empty uses the default.
+func (c *Client) GetTranscodeURL(itemID, sourceID string, limits StreamLimits) string {
+	if sourceID == "" {
+		sourceID = itemID
+	}
+	params := url.Values{}
+	params.Set("MediaSourceId", sourceID)
+	params.Set("DeviceId", c.deviceID)
+	params.Set("VideoCodec", "h264")
+	params.Set("AudioCodec", "aac")
+	if limits.MaxBitrate > 0 {
+		params.Set("MaxStreamingBitrate", strconv.Itoa(limits.MaxBitrate))
+		params.Set("VideoBitrate", strconv.Itoa(max(limits.MaxBitrate-transcodeAudioBitrate, limits.MaxBitrate/2)))
+		params.Set("AudioBitrate", strconv.Itoa(min(transcodeAudioBitrate, limits.MaxBitrate/2)))
+	}
+	if limits.MaxHeight > 0 {
+		params.Set("MaxHeight", strconv.Itoa(limits.MaxHeight))
+	}
+	params.Set("api_key", c.APIKey)
+	return fmt.Sprintf("%s/Videos/%s/master.m3u8?%s", c.ServerURL, itemID, params.Encode())
+}
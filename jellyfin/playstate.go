@@ -0,0 +1,66 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// PlaybackReport describes the playback state of an item, reported so the
+// server can track resume positions and watched status
+type PlaybackReport struct {
+	ItemID        string `json:"ItemId"`
+	MediaSourceID string `json:"MediaSourceId,omitempty"`
+	PositionTicks int64  `json:"PositionTicks"`
+	IsPaused      bool   `json:"IsPaused"`
+	PlayMethod    string `json:"PlayMethod,omitempty"` // "DirectPlay", "DirectStream" or "Transcode"
+}
+
+// ReportPlaybackStart tells the server an item started playing
+func (c *Client) ReportPlaybackStart(ctx context.Context, report PlaybackReport) error {
+	endpoint := fmt.Sprintf("%s/Sessions/Playing?api_key=%s", c.ServerURL, c.APIKey)
+	return c.postJSON(ctx, endpoint, report)
+}
+
+// ReportPlaybackProgress tells the server the current position of an item
+func (c *Client) ReportPlaybackProgress(ctx context.Context, report PlaybackReport) error {
+	endpoint := fmt.Sprintf("%s/Sessions/Playing/Progress?api_key=%s", c.ServerURL, c.APIKey)
+	return c.postJSON(ctx, endpoint, report)
+}
+
+// ReportPlaybackStopped tells the server an item stopped playing and where,
+// which updates its resume position and marks it played if it was finished
+func (c *Client) ReportPlaybackStopped(ctx context.Context, report PlaybackReport) error {
+	endpoint := fmt.Sprintf("%s/Sessions/Playing/Stopped?api_key=%s", c.ServerURL, c.APIKey)
+	return c.postJSON(ctx, endpoint, report)
+}
+
+// SetPlayed marks an item watched or unwatched for the user. Marking a
+// series, season or folder applies to everything in it.
+func (c *Client) SetPlayed(ctx context.Context, itemID string, played bool) error {
+	userID, err := c.userID(ctx)
+	if err != nil {
+		return err
+	}
+
+	method := http.MethodPost
+	if !played {
+		method = http.MethodDelete
+	}
+	endpoint := fmt.Sprintf("%s/Users/%s/PlayedItems/%s?api_key=%s", c.ServerURL, userID, itemID, c.APIKey)
+	_, err = c.do(ctx, method, endpoint)
+	return err
+}
+
+// CountPlayed returns how many items inside a series, season or folder the
+// user has watched
+func (c *Client) CountPlayed(ctx context.Context, parentID string) (int, error) {
+	endpoint := fmt.Sprintf("%s/Items?ParentId=%s&Recursive=true&IsFolder=false&IsPlayed=true&Limit=0&api_key=%s",
+		c.ServerURL, parentID, c.APIKey)
+
+	var page ItemsPage
+	if err := c.getJSON(ctx, c.withUser(ctx, endpoint), &page); err != nil {
+		return 0, err
+	}
+	return page.TotalCount, nil
+}
@@ -0,0 +1,77 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Segment is a part of a video that can be skipped, such as the intro
+type Segment struct {
+	Type  string // "Intro" or "Outro" (the end credits)
+	Start time.Duration
+	End   time.Duration
+}
+
+// GetSegments fetches the intro and end credits of a video from the server's
+// media segments (Jellyfin 10.10 and later), or else the intro from the
+// Intro Skipper plugin. It fails on servers with neither.
+func (c *Client) GetSegments(ctx context.Context, itemID string) ([]Segment, error) {
+	segments, err := c.getMediaSegments(ctx, itemID)
+	if err == nil && len(segments) > 0 {
+		return segments, nil
+	}
+	return c.getIntroTimestamps(ctx, itemID)
+}
+
+// getMediaSegments fetches the intro and credits from /MediaSegments
+func (c *Client) getMediaSegments(ctx context.Context, itemID string) ([]Segment, error) {
+	endpoint := fmt.Sprintf("%s/MediaSegments/%s?api_key=%s", c.ServerURL, itemID, c.APIKey)
+
+	var response struct {
+		Items []struct {
+			Type       string `json:"Type"`
+			StartTicks int64  `json:"StartTicks"`
+			EndTicks   int64  `json:"EndTicks"`
+		} `json:"Items"`
+	}
+	if err := c.getJSON(ctx, endpoint, &response); err != nil {
+		return nil, err
+	}
+
+	var segments []Segment
+	for _, item := range response.Items {
+		if item.Type != "Intro" && item.Type != "Outro" || item.EndTicks <= item.StartTicks {
+			continue
+		}
+		segments = append(segments, Segment{
+			Type:  item.Type,
+			Start: time.Duration(item.StartTicks) * 100,
+			End:   time.Duration(item.EndTicks) * 100,
+		})
+	}
+	return segments, nil
+}
+
+// getIntroTimestamps fetches the intro found by the Intro Skipper plugin
+func (c *Client) getIntroTimestamps(ctx context.Context, itemID string) ([]Segment, error) {
+	endpoint := fmt.Sprintf("%s/Episode/%s/IntroTimestamps?api_key=%s", c.ServerURL, itemID, c.APIKey)
+
+	var intro struct {
+		Valid      bool    `json:"Valid"`
+		IntroStart float64 `json:"IntroStart"` // Seconds
+		IntroEnd   float64 `json:"IntroEnd"`
+	}
+	if err := c.getJSON(ctx, endpoint, &intro); err != nil {
+		return nil, err
+	}
+	if !intro.Valid || intro.IntroEnd <= intro.IntroStart {
+		return nil, nil
+	}
+
+	return []Segment{{
+		Type:  "Intro",
+		Start: time.Duration(intro.IntroStart * float64(time.Second)),
+		End:   time.Duration(intro.IntroEnd * float64(time.Second)),
+	}}, nil
+}
@@ -0,0 +1,53 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Session is a client connected to the server, such as a TV app or a web
+// browser
+type Session struct {
+	ID                    string     `json:"Id"`
+	DeviceID              string     `json:"DeviceId"`
+	DeviceName            string     `json:"DeviceName"`
+	Client                string     `json:"Client"`
+	UserName              string     `json:"UserName"`
+	SupportsRemoteControl bool       `json:"SupportsRemoteControl"`
+	NowPlayingItem        *MediaItem `json:"NowPlayingItem"`
+}
+
+// GetSessions fetches the sessions that can be remote controlled, leaving
+// out this app's own session. Sessions are limited to those the user may
+// control when the user is known.
+func (c *Client) GetSessions(ctx context.Context) ([]Session, error) {
+	endpoint := fmt.Sprintf("%s/Sessions?api_key=%s", c.ServerURL, c.APIKey)
+	if userID, err := c.userID(ctx); err == nil {
+		endpoint += "&ControllableByUserId=" + userID
+	}
+
+	var sessions []Session
+	if err := c.getJSON(ctx, endpoint, &sessions); err != nil {
+		return nil, err
+	}
+
+	controllable := sessions[:0]
+	for _, session := range sessions {
+		if session.SupportsRemoteControl && session.DeviceID != c.deviceID {
+			controllable = append(controllable, session)
+		}
+	}
+	return controllable, nil
+}
+
+// SendPlayCommand tells another session to play an item now, replacing
+// whatever it's playing
+func (c *Client) SendPlayCommand(ctx context.Context, sessionID, itemID string) error {
+	endpoint := fmt.Sprintf("%s/Sessions/%s/Playing?playCommand=PlayNow&itemIds=%s&api_key=%s",
+		c.ServerURL, sessionID, url.QueryEscape(itemID), c.APIKey)
+
+	_, err := c.do(ctx, http.MethodPost, endpoint)
+	return err
+}
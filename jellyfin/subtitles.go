@@ -0,0 +1,90 @@
+package jellyfin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrSubtitlesUnsupported is returned when the server has no remote subtitle
+// provider (such as the OpenSubtitles plugin) to search
+var ErrSubtitlesUnsupported = errors.New("subtitle search is not supported by this server (is a subtitle plugin installed?)")
+
+// RemoteSubtitle is a subtitle offered by one of the server's subtitle providers
+type RemoteSubtitle struct {
+	ID            string  `json:"Id"`
+	Name          string  `json:"Name"`
+	ProviderName  string  `json:"ProviderName"`
+	Format        string  `json:"Format"`
+	Language      string  `json:"ThreeLetterISOLanguageName"`
+	DownloadCount int     `json:"DownloadCount"`
+	Rating        float64 `json:"CommunityRating"`
+	IsHashMatch   bool    `json:"IsHashMatch"`
+}
+
+// SearchSubtitles searches the server's subtitle providers for subtitles in
+// the given three-letter language (e.g. "eng") for an item
+func (c *Client) SearchSubtitles(ctx context.Context, itemID, lang string) ([]RemoteSubtitle, error) {
+	endpoint := fmt.Sprintf("%s/Items/%s/RemoteSearch/Subtitles/%s?api_key=%s",
+		c.ServerURL, itemID, url.PathEscape(lang), c.APIKey)
+
+	body, err := c.do(ctx, http.MethodGet, endpoint)
+	if err != nil {
+		return nil, subtitleError(err)
+	}
+
+	var subtitles []RemoteSubtitle
+	if err := json.Unmarshal(body, &subtitles); err != nil {
+		return nil, err
+	}
+
+	// Without a provider the search succeeds but can never find anything;
+	// say so rather than that there are no subtitles. If the providers can't
+	// be listed, the empty result stands.
+	if len(subtitles) == 0 {
+		if ok, err := c.hasSubtitleProviders(ctx); err == nil && !ok {
+			return nil, ErrSubtitlesUnsupported
+		}
+	}
+
+	return subtitles, nil
+}
+
+// hasSubtitleProviders reports whether the server has any subtitle
+// provider installed, from the subtitle downloaders libraries can enable
+func (c *Client) hasSubtitleProviders(ctx context.Context) (bool, error) {
+	endpoint := fmt.Sprintf("%s/Libraries/AvailableOptions?api_key=%s", c.ServerURL, c.APIKey)
+
+	var options struct {
+		SubtitleFetchers []struct {
+			Name string `json:"Name"`
+		} `json:"SubtitleFetchers"`
+	}
+	if err := c.getJSON(ctx, endpoint, &options); err != nil {
+		return false, err
+	}
+	return len(options.SubtitleFetchers) > 0, nil
+}
+
+// DownloadSubtitle asks the server to download a remote subtitle and attach
+// it to the item, so it's available as a stream the next time it's played
+func (c *Client) DownloadSubtitle(ctx context.Context, itemID, subtitleID string) error {
+	endpoint := fmt.Sprintf("%s/Items/%s/RemoteSearch/Subtitles/%s?api_key=%s",
+		c.ServerURL, itemID, url.PathEscape(subtitleID), c.APIKey)
+
+	_, err := c.do(ctx, http.MethodPost, endpoint)
+	return subtitleError(err)
+}
+
+// subtitleError maps a missing endpoint to ErrSubtitlesUnsupported, for
+// servers older than the subtitle search API
+func subtitleError(err error) error {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return ErrSubtitlesUnsupported
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package jellyfin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// subtitleServer answers subtitle searches with no results, listing the
+// given subtitle providers
+func subtitleServer(providers string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(r.URL.Path, "/RemoteSearch/Subtitles/"):
+			w.Write([]byte(`[]`))
+		case r.URL.Path == "/Libraries/AvailableOptions":
+			w.Write([]byte(`{"SubtitleFetchers": ` + providers + `}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestSearchSubtitlesWithoutProvider(t *testing.T) {
+	server := subtitleServer(`[]`)
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "key").SearchSubtitles(context.Background(), "item", "eng")
+	if !errors.Is(err, ErrSubtitlesUnsupported) {
+		t.Errorf("got error %v, want ErrSubtitlesUnsupported", err)
+	}
+}
+
+func TestSearchSubtitlesNoneFound(t *testing.T) {
+	server := subtitleServer(`[{"Name": "Open Subtitles", "DefaultEnabled": true}]`)
+	defer server.Close()
+
+	subtitles, err := NewClient(server.URL, "key").SearchSubtitles(context.Background(), "item", "eng")
+	if err != nil || len(subtitles) != 0 {
+		t.Errorf("got %v, %v; want no subtitles and no error", subtitles, err)
+	}
+}
@@ -0,0 +1,32 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Ping checks that the server is reachable using its public system info,
+// which answers without authentication
+func (c *Client) Ping(ctx context.Context) error {
+	endpoint := fmt.Sprintf("%s/System/Info/Public", c.ServerURL)
+
+	_, err := c.do(ctx, http.MethodGet, endpoint)
+	return err
+}
+
+// SystemInfo describes the server
+type SystemInfo struct {
+	ServerName string `json:"ServerName"`
+	Version    string `json:"Version"`
+	ID         string `json:"Id"`
+}
+
+// GetSystemInfo fetches the server's details, which requires a valid API key
+func (c *Client) GetSystemInfo(ctx context.Context) (SystemInfo, error) {
+	endpoint := fmt.Sprintf("%s/System/Info?api_key=%s", c.ServerURL, c.APIKey)
+
+	var info SystemInfo
+	err := c.getJSON(ctx, endpoint, &info)
+	return info, err
+}
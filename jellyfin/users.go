@@ -0,0 +1,126 @@
+package jellyfin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// User is a Jellyfin user account
+type User struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Policy struct {
+		IsAdministrator bool `json:"IsAdministrator"`
+	} `json:"Policy"`
+}
+
+// UserData is the per-user state of an item: watched status, resume
+// position and favorites
+type UserData struct {
+	PlaybackPositionTicks int64   `json:"PlaybackPositionTicks"`
+	PlayCount             int     `json:"PlayCount"`
+	IsFavorite            bool    `json:"IsFavorite"`
+	Played                bool    `json:"Played"`
+	PlayedPercentage      float64 `json:"PlayedPercentage"`
+	UnplayedItemCount     int     `json:"UnplayedItemCount"`
+	LastPlayedDate        string  `json:"LastPlayedDate"`
+
+	// Whether the server sent the item's user data. Older servers, and some
+	// item types, leave it out; the zero values then say nothing about the
+	// item rather than that it's unwatched.
+	Known bool `json:"-"`
+}
+
+// UnmarshalJSON decodes user data and marks it known. Items without it, or
+// with null, keep the zero value, which isn't known.
+func (u *UserData) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	type plain UserData // Without this method, to decode as usual
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*u = UserData(decoded)
+	u.Known = true
+	return nil
+}
+
+// LastPlayed returns when the item was last played, or false if it never
+// has been
+func (u UserData) LastPlayed() (time.Time, bool) {
+	if u.LastPlayedDate == "" {
+		return time.Time{}, false
+	}
+	// Jellyfin sends seven fractional digits, which parsing accepts
+	played, err := time.Parse(time.RFC3339, u.LastPlayedDate)
+	if err != nil || played.IsZero() {
+		return time.Time{}, false
+	}
+	return played, true
+}
+
+// GetUsers fetches the server's users
+func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
+	endpoint := fmt.Sprintf("%s/Users?api_key=%s", c.ServerURL, c.APIKey)
+
+	var users []User
+	if err := c.getJSON(ctx, endpoint, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// userRetryInterval is how long a failed lookup of the user is remembered
+// before the server is asked again
+const userRetryInterval = 30 * time.Second
+
+// userID returns the user whose watched state and libraries are used. API
+// keys aren't tied to a user, so when none is configured the first
+// administrator is used.
+func (c *Client) userID(ctx context.Context) (string, error) {
+	c.userMu.Lock()
+	defer c.userMu.Unlock()
+
+	if c.UserID != "" {
+		return c.UserID, nil
+	}
+	if c.userErr != nil && time.Since(c.userErrAt) < userRetryInterval {
+		return "", c.userErr
+	}
+
+	users, err := c.GetUsers(ctx)
+	switch {
+	case isContextError(err):
+		// The caller giving up says nothing about the server
+		return "", fmt.Errorf("failed to look up user: %w", err)
+	case err != nil:
+		return "", c.userLookupFailed(fmt.Errorf("failed to look up user: %w", err))
+	case len(users) == 0:
+		return "", c.userLookupFailed(errors.New("the server has no users"))
+	}
+	c.userErr = nil
+
+	c.UserID = users[0].ID
+	for _, user := range users {
+		if user.Policy.IsAdministrator {
+			c.UserID = user.ID
+			break
+		}
+	}
+
+	return c.UserID, nil
+}
+
+// userLookupFailed remembers why the user couldn't be looked up, with
+// c.userMu held, and returns it
+func (c *Client) userLookupFailed(err error) error {
+	c.userErr, c.userErrAt = err, time.Now()
+	return err
+}
@@ -0,0 +1,73 @@
+package jellyfin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestUserDataKnown(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		known  bool
+		played bool
+	}{
+		{"missing", `{"Id": "item"}`, false, false},
+		{"null", `{"Id": "item", "UserData": null}`, false, false},
+		{"empty", `{"Id": "item", "UserData": {}}`, true, false},
+		{"unwatched", `{"Id": "item", "UserData": {"Played": false, "PlayCount": 0}}`, true, false},
+		{"watched", `{"Id": "item", "UserData": {"Played": true, "PlayCount": 2}}`, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var item MediaItem
+			if err := json.Unmarshal([]byte(tt.json), &item); err != nil {
+				t.Fatal(err)
+			}
+			if item.UserData.Known != tt.known || item.UserData.Played != tt.played {
+				t.Errorf("Known, Played = %v, %v; want %v, %v",
+					item.UserData.Known, item.UserData.Played, tt.known, tt.played)
+			}
+		})
+	}
+}
+
+func TestUserLookupFailureRemembered(t *testing.T) {
+	var lookups atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	c := NewClient(server.URL, "key")
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.userID(context.Background()); err == nil {
+			t.Fatal("looking up the user succeeded against a failing server")
+		}
+	}
+	if n := lookups.Load(); n != 1 {
+		t.Errorf("asked the server %d times, want once until userRetryInterval has passed", n)
+	}
+
+	c.userErrAt = c.userErrAt.Add(-userRetryInterval)
+	c.userID(context.Background())
+	if n := lookups.Load(); n != 2 {
+		t.Errorf("asked the server %d times, want again after userRetryInterval", n)
+	}
+}
+
+func TestUserLookupCancelledNotRemembered(t *testing.T) {
+	c := NewClient("http://jellyfin.invalid", "key")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c.userID(ctx)
+	if c.userErr != nil {
+		t.Errorf("remembered %v, though only the caller gave up", c.userErr)
+	}
+}
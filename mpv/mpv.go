@@ -0,0 +1,240 @@
+// Package mpv controls a running mpv player over its JSON IPC socket
+// (started with --input-ipc-server).
+package mpv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported is returned on platforms where mpv's IPC server isn't a
+// Unix socket (Windows uses named pipes)
+var ErrUnsupported = errors.New("mpv IPC is not supported on this platform")
+
+// ErrPropertyUnavailable is returned for properties mpv doesn't have a value
+// for yet, such as the position before the file has loaded
+var ErrPropertyUnavailable = errors.New("property unavailable")
+
+// commandTimeout bounds each command so a hung player can't block the caller
+const commandTimeout = 5 * time.Second
+
+// Conn is a connection to a running mpv
+type Conn struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	nextID int
+}
+
+// SocketPath returns a path for the IPC socket of a new player
+func SocketPath(name string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return "", ErrUnsupported
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("%s-%d.sock", name, time.Now().UnixNano())), nil
+}
+
+// Dial connects to mpv's IPC socket, waiting for the player to create it
+// until ctx is done
+func Dial(ctx context.Context, path string) (*Conn, error) {
+	var dialer net.Dialer
+	for {
+		conn, err := dialer.DialContext(ctx, "unix", path)
+		if err == nil {
+			return &Conn{conn: conn, reader: bufio.NewReader(conn)}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to connect to mpv: %w", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// Close closes the connection, leaving the player running
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// response is mpv's reply to a command, or an event
+type response struct {
+	RequestID int             `json:"request_id"`
+	Error     string          `json:"error"`
+	Data      json.RawMessage `json:"data"`
+	Event     string          `json:"event"`
+	ID        int             `json:"id"` // Observation a property-change event is for
+}
+
+// Command runs an mpv command, e.g. Command("seek", 10), and returns its data
+func (c *Conn) Command(args ...interface{}) (json.RawMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.command(args...)
+}
+
+// command runs a command with c.mu held
+func (c *Conn) command(args ...interface{}) (json.RawMessage, error) {
+	c.nextID++
+	id := c.nextID
+
+	request, err := json.Marshal(map[string]interface{}{"command": args, "request_id": id})
+	if err != nil {
+		return nil, err
+	}
+
+	c.conn.SetDeadline(time.Now().Add(commandTimeout))
+	if _, err := c.conn.Write(append(request, '\n')); err != nil {
+		return nil, err
+	}
+
+	// Skip events, replies to earlier commands that timed out, and the rest
+	// of any line an interrupted read left behind
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		var resp response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			continue
+		}
+		if resp.Event != "" || resp.RequestID != id {
+			continue
+		}
+
+		switch resp.Error {
+		case "success":
+			return resp.Data, nil
+		case "property unavailable":
+			return nil, ErrPropertyUnavailable
+		}
+		return nil, errors.New(resp.Error)
+	}
+}
+
+// observeID identifies WaitForChange's property observation
+const observeID = 1
+
+// WaitForChange blocks until a property differs from its value when called,
+// or ctx is done. mpv notifies the change, so nothing is polled meanwhile.
+func (c *Conn) WaitForChange(ctx context.Context, property string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current, err := c.command("get_property", property)
+	if err != nil && !errors.Is(err, ErrPropertyUnavailable) {
+		return err
+	}
+	if _, err := c.command("observe_property", observeID, property); err != nil {
+		return err
+	}
+	defer c.command("unobserve_property", observeID)
+
+	// Wait without a deadline, interrupting the read if ctx is done
+	c.conn.SetDeadline(time.Time{})
+	stop := context.AfterFunc(ctx, func() { c.conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		var resp response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			continue
+		}
+		if resp.Event == "property-change" && resp.ID == observeID && !bytes.Equal(resp.Data, current) {
+			return nil
+		}
+	}
+}
+
+// Get reads a property into v
+func (c *Conn) Get(property string, v interface{}) error {
+	data, err := c.Command("get_property", property)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Position returns the playback position in seconds
+func (c *Conn) Position() (float64, error) {
+	var position float64
+	err := c.Get("time-pos", &position)
+	return position, err
+}
+
+// Duration returns the length of the file in seconds
+func (c *Conn) Duration() (float64, error) {
+	var duration float64
+	err := c.Get("duration", &duration)
+	return duration, err
+}
+
+// Paused reports whether playback is paused
+func (c *Conn) Paused() (bool, error) {
+	var paused bool
+	err := c.Get("pause", &paused)
+	return paused, err
+}
+
+// Seek jumps to a position in seconds from the start
+func (c *Conn) Seek(position float64) error {
+	_, err := c.Command("seek", position, "absolute")
+	return err
+}
+
+// ShowText shows a message on the player's OSD for a while
+func (c *Conn) ShowText(text string, duration time.Duration) error {
+	_, err := c.Command("show-text", text, duration.Milliseconds())
+	return err
+}
+
+// Track is a video, audio or subtitle track of the playing file
+type Track struct {
+	ID       int    `json:"id"`
+	Type     string `json:"type"` // "video", "audio" or "sub"
+	Title    string `json:"title"`
+	Lang     string `json:"lang"`
+	Selected bool   `json:"selected"`
+}
+
+// Tracks returns the tracks of the playing file
+func (c *Conn) Tracks() ([]Track, error) {
+	var tracks []Track
+	err := c.Get("track-list", &tracks)
+	return tracks, err
+}
+
+// Cycle steps a property to its next value, e.g. the next subtitle track
+// with Cycle("sub")
+func (c *Conn) Cycle(property string) error {
+	_, err := c.Command("cycle", property)
+	return err
+}
+
+// PlaylistPos returns the index of the playing file in the playlist
+func (c *Conn) PlaylistPos() (int, error) {
+	var index int
+	err := c.Get("playlist-pos", &index)
+	return index, err
+}